@@ -0,0 +1,31 @@
+//Package echoqb serves GormAdapter queries from echo handlers
+package echoqb
+
+import (
+	"github.com/akacokafor/gorm-query-builder/pkg/querybuilder"
+	"github.com/labstack/echo/v4"
+)
+
+//AdapterFactory builds the adapter for a request, adapters hold per query state so a new one is needed per request
+type AdapterFactory func(c echo.Context) *querybuilder.GormAdapter
+
+//DestFactory returns a pointer to a new slice to load the page into, e.g. func() interface{} { return &[]User{} }
+type DestFactory func() interface{}
+
+//Options parses the query parameters of the request
+func Options(c echo.Context) (*querybuilder.Options, error) {
+	return querybuilder.ParseUrl(querybuilder.RequestUrl(c.Request()))
+}
+
+//Handler paginates the query of the request into a new dest and writes it with the pagination meta and absolute links,
+//invalid query parameters are answered with a 400 JSON:API errors document. Errors are returned after the response
+//is written, so that middleware can log the failures behind a 500, echo does not overwrite a committed response
+func Handler(newAdapter AdapterFactory, newDest DestFactory) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		status, body, err := newAdapter(c).PaginateResponse(c.Request(), newDest())
+		if writeErr := c.JSON(status, body); writeErr != nil {
+			return writeErr
+		}
+		return err
+	}
+}
@@ -0,0 +1,105 @@
+package echoqb_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/akacokafor/gorm-query-builder/pkg/querybuilder"
+	"github.com/akacokafor/gorm-query-builder/pkg/querybuilder/echoqb"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type user struct {
+	ID     uint   `json:"id"`
+	Name   string `json:"name"`
+	Status string `json:"status"`
+}
+
+func newAdapter(db *gorm.DB) *querybuilder.GormAdapter {
+	return querybuilder.NewGormAdapter(db.Model(&user{})).
+		AllowedFilters([]interface{}{querybuilder.NewGormAllowedFilterExact("status")}).
+		AllowedSorts([]interface{}{"id"})
+}
+
+func TestHandler(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:echoqb?mode=memory&cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&user{}); err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i <= 12; i++ {
+		db.Create(&user{Name: fmt.Sprintf("user %d", i), Status: []string{"open", "closed"}[i%2]})
+	}
+
+	var handlerError error
+	router := echo.New()
+	router.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			handlerError = next(c)
+			return handlerError
+		}
+	})
+	router.GET("/users", echoqb.Handler(func(c echo.Context) *querybuilder.GormAdapter {
+		return newAdapter(db)
+	}, func() interface{} {
+		return &[]user{}
+	}))
+	router.GET("/missing", echoqb.Handler(func(c echo.Context) *querybuilder.GormAdapter {
+		return querybuilder.NewGormAdapter(db.Table("missing_users"))
+	}, func() interface{} {
+		return &[]user{}
+	}))
+	serve := func(target string) (int, []byte) {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, target, nil))
+		return recorder.Code, recorder.Body.Bytes()
+	}
+
+	t.Run("Should write the page with pagination meta", func(t *testing.T) {
+		status, body := serve("/users?filter[status]=open&sort=-id&page=2&size=4")
+		assert.Equal(t, http.StatusOK, status)
+
+		var document struct {
+			Data  []user                       `json:"data"`
+			Meta  querybuilder.PaginationMeta  `json:"meta"`
+			Links querybuilder.PaginationLinks `json:"links"`
+		}
+		assert.Nil(t, json.Unmarshal(body, &document))
+		assert.Len(t, document.Data, 2)
+		assert.Equal(t, uint(4), document.Data[0].ID)
+		assert.Equal(t, int64(6), document.Meta.Total)
+		assert.Equal(t, 2, document.Meta.CurrentPage)
+		assert.Equal(t, 2, document.Meta.LastPage)
+		assert.Equal(t, "http://example.com/users?filter[status]=open&page=1&size=4&sort=-id", document.Links.Prev)
+	})
+
+	t.Run("Should write a JSON:API error document for invalid queries", func(t *testing.T) {
+		status, body := serve("/users?filter[password]=secret")
+		assert.Equal(t, http.StatusBadRequest, status)
+
+		var document struct {
+			Errors []struct {
+				Code   string            `json:"code"`
+				Source map[string]string `json:"source"`
+			} `json:"errors"`
+		}
+		assert.Nil(t, json.Unmarshal(body, &document))
+		assert.Len(t, document.Errors, 1)
+		assert.Equal(t, querybuilder.ErrorCodeInvalidFilter, document.Errors[0].Code)
+		assert.Equal(t, "filter[password]", document.Errors[0].Source["parameter"])
+	})
+	t.Run("Should return the error behind a 500 after writing the response", func(t *testing.T) {
+		status, body := serve("/missing")
+		assert.Equal(t, http.StatusInternalServerError, status)
+		assert.Contains(t, string(body), querybuilder.ErrorCodeInternal)
+		assert.Contains(t, fmt.Sprint(handlerError), "missing_users")
+	})
+}
@@ -0,0 +1,11 @@
+module github.com/akacokafor/gorm-query-builder/pkg/querybuilder/echoqb
+
+go 1.16
+
+require (
+	github.com/akacokafor/gorm-query-builder v0.1.0
+	github.com/labstack/echo/v4 v4.6.3
+	github.com/stretchr/testify v1.7.0
+	gorm.io/driver/sqlite v1.1.4
+	gorm.io/gorm v1.21.12
+)
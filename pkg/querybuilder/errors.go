@@ -0,0 +1,187 @@
+package querybuilder
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	ErrorCodeInvalidParameter      = "invalid_parameter"
+	ErrorCodeInvalidFilter         = "invalid_filter"
+	ErrorCodeInvalidFilterOperator = "invalid_filter_operator"
+	ErrorCodeInvalidFilterValue    = "invalid_filter_value"
+	ErrorCodeInvalidSort           = "invalid_sort"
+	ErrorCodeInvalidInclude        = "invalid_include"
+	ErrorCodeInvalidField          = "invalid_field"
+	ErrorCodeInvalidPage           = "invalid_page"
+	ErrorCodeInvalidSize           = "invalid_size"
+	ErrorCodeInvalidCursor         = "invalid_cursor"
+	ErrorCodeInternal              = "internal_error"
+)
+
+var errorTitles = map[string]string{
+	ErrorCodeInvalidParameter:      "Invalid query parameter",
+	ErrorCodeInvalidFilter:         "Invalid filter",
+	ErrorCodeInvalidFilterOperator: "Invalid filter operator",
+	ErrorCodeInvalidFilterValue:    "Invalid filter value",
+	ErrorCodeInvalidSort:           "Invalid sort",
+	ErrorCodeInvalidInclude:        "Invalid include",
+	ErrorCodeInvalidField:          "Invalid field",
+	ErrorCodeInvalidPage:           "Invalid page",
+	ErrorCodeInvalidSize:           "Invalid page size",
+	ErrorCodeInvalidCursor:         "Invalid cursor",
+	ErrorCodeInternal:              "Internal server error",
+}
+
+//clientErrors maps the errors caused by the query parameters to their error code and parameter
+var clientErrors = []struct {
+	err       error
+	code      string
+	parameter string
+}{
+	{ErrInvalidFilterOperator, ErrorCodeInvalidFilterOperator, "filter"},
+	{ErrInvalidFilterValue, ErrorCodeInvalidFilterValue, "filter"},
+	{ErrInvalidFilterQuery, ErrorCodeInvalidFilter, "filter"},
+	{ErrInvalidSortQuery, ErrorCodeInvalidSort, "sort"},
+	{ErrInvalidFieldQuery, ErrorCodeInvalidField, "fields"},
+	{ErrInvalidIncludeQuery, ErrorCodeInvalidInclude, "include"},
+	{ErrInvalidCursorQuery, ErrorCodeInvalidCursor, "cursor"},
+	{ErrInvalidPageSize, ErrorCodeInvalidSize, "size"},
+}
+
+//QueryParameterError describes a single problem with a query parameter, Err holds the matching sentinel error
+type QueryParameterError struct {
+	Code      string
+	Parameter string
+	Value     string
+	Allowed   []string
+	Detail    string
+	Err       error
+}
+
+func (e *QueryParameterError) Error() string {
+	if e.Err == nil {
+		return e.Detail
+	}
+	return fmt.Sprintf("%s, %s", e.Detail, e.Err.Error())
+}
+
+func (e *QueryParameterError) Unwrap() error {
+	return e.Err
+}
+
+//QueryValidationError aggregates every problem found in the query parameters of a request
+type QueryValidationError struct {
+	Errors []*QueryParameterError
+}
+
+func (e *QueryValidationError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, parameterError := range e.Errors {
+		messages = append(messages, parameterError.Error())
+	}
+	return strings.Join(messages, "; ")
+}
+
+//Is reports whether any of the aggregated errors matches the target, e.g. ErrInvalidFilterQuery
+func (e *QueryValidationError) Is(target error) bool {
+	for _, parameterError := range e.Errors {
+		if errors.Is(parameterError, target) {
+			return true
+		}
+	}
+	return false
+}
+
+//Add appends the error, errors that are not a *QueryParameterError are recorded with the fallback code and parameter
+func (e *QueryValidationError) Add(err error, code string, parameter string) {
+	var validationError *QueryValidationError
+	if errors.As(err, &validationError) {
+		e.Errors = append(e.Errors, validationError.Errors...)
+		return
+	}
+
+	var parameterError *QueryParameterError
+	if errors.As(err, &parameterError) {
+		e.Errors = append(e.Errors, parameterError)
+		return
+	}
+	e.Errors = append(e.Errors, &QueryParameterError{
+		Code:      code,
+		Parameter: parameter,
+		Detail:    err.Error(),
+		Err:       err,
+	})
+}
+
+func (e *QueryValidationError) HasErrors() bool {
+	return len(e.Errors) > 0
+}
+
+type jsonAPIError struct {
+	Status string                 `json:"status"`
+	Code   string                 `json:"code"`
+	Title  string                 `json:"title"`
+	Detail string                 `json:"detail"`
+	Source map[string]string      `json:"source"`
+	Meta   map[string]interface{} `json:"meta,omitempty"`
+}
+
+//MarshalJSON renders the errors as a JSON:API errors document
+func (e *QueryValidationError) MarshalJSON() ([]byte, error) {
+	document := struct {
+		Errors []jsonAPIError `json:"errors"`
+	}{Errors: make([]jsonAPIError, 0, len(e.Errors))}
+
+	for _, parameterError := range e.Errors {
+		item := jsonAPIError{
+			Status: fmt.Sprint(http.StatusBadRequest),
+			Code:   parameterError.Code,
+			Title:  errorTitles[parameterError.Code],
+			Detail: parameterError.Detail,
+			Source: map[string]string{"parameter": parameterError.Parameter},
+		}
+		if len(parameterError.Allowed) > 0 || parameterError.Value != "" {
+			item.Meta = map[string]interface{}{}
+			if len(parameterError.Allowed) > 0 {
+				item.Meta["allowed"] = parameterError.Allowed
+			}
+			if parameterError.Value != "" {
+				item.Meta["value"] = parameterError.Value
+			}
+		}
+		document.Errors = append(document.Errors, item)
+	}
+	return json.Marshal(document)
+}
+
+//ErrorResponse maps an error returned by the adapter to the HTTP status and the JSON:API errors document to respond with,
+//problems with the query parameters are a 400, anything else is a 500 that does not leak the underlying error
+func ErrorResponse(err error) (int, interface{}) {
+	var validation *QueryValidationError
+	if errors.As(err, &validation) {
+		return http.StatusBadRequest, validation
+	}
+
+	for _, clientError := range clientErrors {
+		if errors.Is(err, clientError.err) {
+			validation = &QueryValidationError{}
+			validation.Add(err, clientError.code, clientError.parameter)
+			return http.StatusBadRequest, validation
+		}
+	}
+
+	document := struct {
+		Errors []jsonAPIError `json:"errors"`
+	}{Errors: []jsonAPIError{{
+		Status: fmt.Sprint(http.StatusInternalServerError),
+		Code:   ErrorCodeInternal,
+		Title:  errorTitles[ErrorCodeInternal],
+		Detail: "the query could not be executed",
+		Source: map[string]string{},
+	}}}
+	return http.StatusInternalServerError, document
+}
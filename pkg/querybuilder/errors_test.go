@@ -0,0 +1,74 @@
+package querybuilder_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/akacokafor/gorm-query-builder/pkg/querybuilder"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestQueryValidationError(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = querybuilder.NewGormAdapter(db.Table("users")).
+		AllowedFilters([]interface{}{
+			"name",
+			querybuilder.NewGormAllowedFilterOperator("age", querybuilder.FilterOperatorGte),
+		}).
+		AllowedSorts([]interface{}{"created_at"}).
+		AllowedFields([]interface{}{"id", "name"}).
+		ExecuteOnUrl("https://example.com?filter[active]=1&filter[age][lt]=5&sort=-age&fields[users]=password&page=first&size=ten")
+
+	var validationError *querybuilder.QueryValidationError
+	assert.True(t, errors.As(err, &validationError))
+	assert.True(t, errors.Is(err, querybuilder.ErrInvalidFilterQuery))
+	assert.True(t, errors.Is(err, querybuilder.ErrInvalidFilterOperator))
+	assert.True(t, errors.Is(err, querybuilder.ErrInvalidSortQuery))
+	assert.True(t, errors.Is(err, querybuilder.ErrInvalidFieldQuery))
+
+	var codes []string
+	var parameters []string
+	for _, parameterError := range validationError.Errors {
+		codes = append(codes, parameterError.Code)
+		parameters = append(parameters, parameterError.Parameter)
+	}
+	assert.ElementsMatch(t, []string{
+		querybuilder.ErrorCodeInvalidPage,
+		querybuilder.ErrorCodeInvalidSize,
+		querybuilder.ErrorCodeInvalidFilter,
+		querybuilder.ErrorCodeInvalidFilterOperator,
+		querybuilder.ErrorCodeInvalidSort,
+		querybuilder.ErrorCodeInvalidField,
+	}, codes)
+	assert.ElementsMatch(t, []string{"page", "size", "filter[active]", "filter[age]", "sort", "fields[users]"}, parameters)
+
+	document, err := json.Marshal(validationError)
+	assert.Nil(t, err)
+
+	var decoded struct {
+		Errors []struct {
+			Status string            `json:"status"`
+			Code   string            `json:"code"`
+			Source map[string]string `json:"source"`
+			Meta   struct {
+				Allowed []string `json:"allowed"`
+			} `json:"meta"`
+		} `json:"errors"`
+	}
+	assert.Nil(t, json.Unmarshal(document, &decoded))
+	assert.Len(t, decoded.Errors, 6)
+	for _, item := range decoded.Errors {
+		assert.Equal(t, "400", item.Status)
+		if item.Code == querybuilder.ErrorCodeInvalidFilter {
+			assert.Equal(t, "filter[active]", item.Source["parameter"])
+			assert.Equal(t, []string{"name", "age"}, item.Meta.Allowed)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+//Package fiberqb serves GormAdapter queries from fiber handlers
+package fiberqb
+
+import (
+	"net/http"
+
+	"github.com/akacokafor/gorm-query-builder/pkg/querybuilder"
+	"github.com/gofiber/fiber/v2"
+)
+
+//AdapterFactory builds the adapter for a request, adapters hold per query state so a new one is needed per request
+type AdapterFactory func(c *fiber.Ctx) *querybuilder.GormAdapter
+
+//DestFactory returns a pointer to a new slice to load the page into, e.g. func() interface{} { return &[]User{} }
+type DestFactory func() interface{}
+
+//Options parses the query parameters of the request
+func Options(c *fiber.Ctx) (*querybuilder.Options, error) {
+	return querybuilder.ParseUrl(requestUrl(c))
+}
+
+//Handler paginates the query of the request into a new dest and writes it with the pagination meta and absolute links,
+//invalid query parameters are answered with a 400 JSON:API errors document. The failures behind a 500 are logged with
+//the logger of the request context rather than returned, fiber's error handler would replace the written response
+func Handler(newAdapter AdapterFactory, newDest DestFactory) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		status, body, err := newAdapter(c).PaginateUrlResponse(requestUrl(c), newDest())
+		if err != nil && status >= http.StatusInternalServerError {
+			c.Context().Logger().Printf("querybuilder: %v", err)
+		}
+		return c.Status(status).JSON(body)
+	}
+}
+
+//requestUrl returns the absolute url of the request, fiber is not built on net/http so querybuilder.RequestUrl can not be used
+func requestUrl(c *fiber.Ctx) string {
+	return c.BaseURL() + c.OriginalURL()
+}
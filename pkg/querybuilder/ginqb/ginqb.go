@@ -0,0 +1,34 @@
+//Package ginqb serves GormAdapter queries from gin handlers
+package ginqb
+
+import (
+	"github.com/akacokafor/gorm-query-builder/pkg/querybuilder"
+	"github.com/gin-gonic/gin"
+)
+
+//AdapterFactory builds the adapter for a request, adapters hold per query state so a new one is needed per request
+type AdapterFactory func(c *gin.Context) *querybuilder.GormAdapter
+
+//DestFactory returns a pointer to a new slice to load the page into, e.g. func() interface{} { return &[]User{} }
+type DestFactory func() interface{}
+
+//Options parses the query parameters of the request
+func Options(c *gin.Context) (*querybuilder.Options, error) {
+	return querybuilder.ParseUrl(querybuilder.RequestUrl(c.Request))
+}
+
+//Handler paginates the query of the request into a new dest and writes it with the pagination meta and absolute links,
+//invalid query parameters are answered with a 400 JSON:API errors document. Errors are added to c.Errors, so that
+//the failures behind a 500 can be logged
+func Handler(newAdapter AdapterFactory, newDest DestFactory) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		status, body, err := newAdapter(c).PaginateResponse(c.Request, newDest())
+		if err != nil {
+			_ = c.Error(err)
+			c.AbortWithStatusJSON(status, body)
+			return
+		}
+
+		c.JSON(status, body)
+	}
+}
@@ -9,21 +9,55 @@ import (
 var (
 	ErrInvalidFilterQuery = errors.New("filters contains an invalid filter")
 	ErrInvalidSortQuery = errors.New("sorts contains an invalid sort")
+	ErrInvalidFieldQuery = errors.New("fields contains an invalid field")
+	ErrInvalidFilterOperator = errors.New("filters contains an invalid operator")
+	ErrInvalidCursorQuery = errors.New("cursor is invalid")
+	ErrInvalidFilterValue = errors.New("filters contains an invalid value")
+	ErrInvalidPageSize = errors.New("size is invalid")
+	ErrInvalidIncludeQuery = errors.New("includes contains an invalid include")
 )
 
 
 
+//queryConfig holds the whitelists and defaults shared by GormAdapter and QueryDefinition
+type queryConfig struct {
+	model             interface{}
+	filtersWhitelist  []interface{}
+	sortWhitelist     []interface{}
+	fieldsWhiteList   []interface{}
+	includesWhitelist []interface{}
+	defaultSort       Sortable
+	cursorTieBreaker  string
+	timeLayouts       []string
+	defaultPage       int
+	defaultPageSize   int
+	maxPageSize       int
+	allowedPageSizes  []int
+	pageSizeMode      PageSizeMode
+	searchColumns     []string
+	searchBackend     GormSearchBackend
+	baseUrl           string
+	filterDelimiter   *string
+	unlistedIncludes  bool
+	maxIncludeDepth   int
+}
+
+//GormAdapter applies the query parameters to its db, it is mutated while applying them and must not be shared
+//between requests, use a QueryDefinition for that
 type GormAdapter struct {
+	queryConfig
 	db                  *gorm.DB
-	filtersWhitelist    []interface{}
-	sortWhitelist       []interface{}
-	fieldsWhiteList     []interface{}
-	includesWhitelist   []interface{}
-	defaultSort         Sortable
 	defaultToPagination bool
-	defaultPage         int
-	defaultSize         int
 	relationships       []string
+	requestUrl          string
+	options             OptionsInterface
+}
+
+//FilterDelimiter sets the delimiter the *OnUrl methods split filter values on, an empty delimiter disables splitting,
+//defaults to DefaultFilterDelimiter
+func (g *GormAdapter) FilterDelimiter(delimiter string) *GormAdapter {
+	g.filterDelimiter = &delimiter
+	return g
 }
 
 //AllowedFilters white lists only the acceptable filters that can be applied from the query parameters
@@ -33,13 +67,27 @@ func (g *GormAdapter) AllowedFilters(filtersWhitelist []interface{}) *GormAdapte
 }
 
 
-//AllowedIncludes white lists only the acceptable includes that can be applied from the query parameters
+//AllowedIncludes white lists only the acceptable includes that can be applied from the query parameters,
+//without a white list every include is rejected unless AllowUnlistedIncludes is enabled
 func (g *GormAdapter) AllowedIncludes(includesWhitelist []interface{}) *GormAdapter {
 	g.includesWhitelist = includesWhitelist
 	return g
 }
 
-//AllowedIncludes white lists only the acceptable includes that can be applied from the query parameters
+//AllowUnlistedIncludes preloads any relationship requested by the client when no includes are white listed
+func (g *GormAdapter) AllowUnlistedIncludes(allow bool) *GormAdapter {
+	g.unlistedIncludes = allow
+	return g
+}
+
+//MaxIncludeDepth limits the nesting of includes, e.g. posts.comments has a depth of 2, 0 disables the limit
+func (g *GormAdapter) MaxIncludeDepth(depth int) *GormAdapter {
+	g.maxIncludeDepth = depth
+	return g
+}
+
+//AllowedFields white lists only the acceptable fields that can be selected from the query parameters.
+//Fields of the root table can be listed as "name" or "users.name", fields of an include as "wallet.balance"
 func (g *GormAdapter) AllowedFields(fieldsWhiteList []interface{}) *GormAdapter {
 	g.fieldsWhiteList = fieldsWhiteList
 	return g
@@ -51,24 +99,68 @@ func (g *GormAdapter) DefaultSort(defaultSort Sortable) *GormAdapter {
 	return g
 }
 
+//CursorTieBreaker sets the unique column appended to the sorts in cursor pagination, defaults to id
+func (g *GormAdapter) CursorTieBreaker(column string) *GormAdapter {
+	g.cursorTieBreaker = column
+	return g
+}
+
 //AllowedSorts white lists only the acceptable sort columns that can be applied from the query parameters
 func (g *GormAdapter) AllowedSorts(sortWhitelist []interface{}) *GormAdapter {
 	g.sortWhitelist = sortWhitelist
 	return g
 }
 
+//DefaultPage sets the page paginated queries start at when no page is requested, defaults to 1
+func (g *GormAdapter) DefaultPage(page int) *GormAdapter {
+	g.defaultPage = page
+	return g
+}
+
+//DefaultPageSize sets the size of a page when no size is requested, defaults to DefaultPageSize
+func (g *GormAdapter) DefaultPageSize(size int) *GormAdapter {
+	g.defaultPageSize = size
+	return g
+}
+
+//MaxPageSize limits the size a client can request, 0 disables the limit
+func (g *GormAdapter) MaxPageSize(size int) *GormAdapter {
+	g.maxPageSize = size
+	return g
+}
+
+//AllowedPageSizes white lists the sizes a client can request
+func (g *GormAdapter) AllowedPageSizes(sizes ...int) *GormAdapter {
+	g.allowedPageSizes = sizes
+	return g
+}
+
+//PageSizeMode sets whether invalid page sizes are clamped, the default, or rejected with ErrInvalidPageSize
+func (g *GormAdapter) PageSizeMode(mode PageSizeMode) *GormAdapter {
+	g.pageSizeMode = mode
+	return g
+}
+
+//Definition returns a snapshot of the whitelists and defaults of the adapter that can be shared between goroutines
+func (g *GormAdapter) Definition() *QueryDefinition {
+	return &QueryDefinition{queryConfig: g.queryConfig.clone()}
+}
+
 
 func (g *GormAdapter) ExecuteOnUrl(url string) (*gorm.DB, error) {
-	optionsInstance, err := ParseUrl(url)
+	optionsInstance, err := g.parseUrl(url)
 	if err != nil {
 		return g.db, err
 	}
+	g.requestUrl = url
 
 	return g.Execute(optionsInstance)
 }
 
 func (g *GormAdapter) Execute(optionsInstance OptionsInterface) (*gorm.DB, error) {
-	if err := g.validate(optionsInstance); err != nil {
+	g.options = optionsInstance
+	optionsInstance, err := g.validate(optionsInstance)
+	if err != nil {
 		return g.db, err
 	}
 	if err := g.applyOptions(optionsInstance); err != nil {
@@ -81,8 +173,6 @@ func (g *GormAdapter) Execute(optionsInstance OptionsInterface) (*gorm.DB, error
 func (g *GormAdapter) Paginate(optionsInstance OptionsInterface) (*gorm.DB, error) {
 
 	g.defaultToPagination = true
-	g.defaultPage = 1
-	g.defaultSize = 30
 
 	if _, err := g.Execute(optionsInstance); err != nil {
 		return g.db, err
@@ -91,16 +181,44 @@ func (g *GormAdapter) Paginate(optionsInstance OptionsInterface) (*gorm.DB, erro
 	return g.db, nil
 }
 
-func (g *GormAdapter) validate(instance OptionsInterface) error {
-	if err := g.validateFilters(instance); err != nil {
-		return err
+//validate collects every problem with the query parameters into a *QueryValidationError,
+//other errors are returned for an invalid adapter configuration. The returned options hold the coerced filter values
+func (g *GormAdapter) validate(instance OptionsInterface) (OptionsInterface, error) {
+	validation := &QueryValidationError{}
+
+	if errorsGetter, ok := instance.(interface{ GetErrors() []error }); ok {
+		for _, err := range errorsGetter.GetErrors() {
+			validation.Add(err, ErrorCodeInvalidParameter, "")
+		}
 	}
 
-	if err := g.validateSorts(instance); err != nil {
-		return err
+	instance = g.applyFilterDefaults(instance)
+
+	if err := g.validateFilters(instance, validation); err != nil {
+		return nil, err
 	}
 
-	return nil
+	instance = g.coerceFilters(instance, validation)
+
+	if err := g.validateSorts(instance, validation); err != nil {
+		return nil, err
+	}
+
+	if err := g.validateFields(instance, validation); err != nil {
+		return nil, err
+	}
+
+	if err := g.validateIncludes(instance, validation); err != nil {
+		return nil, err
+	}
+
+	g.validatePageSize(instance, validation)
+
+	if validation.HasErrors() {
+		return nil, validation
+	}
+
+	return instance, nil
 }
 
 func (g *GormAdapter) applyOptions(instance OptionsInterface) error {
@@ -117,6 +235,10 @@ func (g *GormAdapter) applyOptions(instance OptionsInterface) error {
 		return err
 	}
 
+	if err := g.applyFields(instance); err != nil {
+		return err
+	}
+
 	if err := g.applyIncludes(instance); err != nil {
 		return err
 	}
@@ -129,6 +251,11 @@ func (g *GormAdapter) applyOptions(instance OptionsInterface) error {
 }
 
 func (g *GormAdapter) normalizeIncludeName(name string) string {
+	return normalizeIncludeName(name)
+}
+
+//normalizeIncludeName converts an include such as posts.bank_account to the association name Posts.BankAccount
+func normalizeIncludeName(name string) string {
 	var sb strings.Builder
 	componentParts := strings.Split(name,".")
 	for index, part := range componentParts {
@@ -158,27 +285,32 @@ func toCamelCase(part string) string {
 }
 
 func (g *GormAdapter) applyPagination(instance OptionsInterface) error {
-	currentPage := instance.GetPage()
-	if g.defaultToPagination && currentPage == nil  {
-		currentPage = &g.defaultPage
-	}
-
-	if currentPage == nil {
+	page, size, ok := g.getPagination(instance)
+	if !ok {
 		return nil
 	}
 
-	sizeAddr := instance.GetSize()
-	if sizeAddr == nil {
-		sizeAddr  = &g.defaultSize
-	}
-
-	page := *currentPage
-	size := *sizeAddr
 	offset := (page - 1) * size
 	g.db.Offset(offset).Limit(size)
 	return nil
 }
 
+//getPagination returns the page and size to paginate with, ok is false when the query should not be paginated
+func (g *GormAdapter) getPagination(instance OptionsInterface) (page int, size int, ok bool) {
+	currentPage := instance.GetPage()
+	if currentPage == nil && !g.defaultToPagination {
+		return 0, 0, false
+	}
+
+	page = g.getDefaultPage()
+	if currentPage != nil {
+		page = *currentPage
+	}
+
+	size, _ = g.getPageSize(instance)
+	return page, size, true
+}
+
 func (g *GormAdapter) addRelationship(name string) {
 	g.relationships = append(g.relationships, name)
 }
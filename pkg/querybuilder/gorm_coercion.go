@@ -0,0 +1,242 @@
+package querybuilder
+
+import (
+	"database/sql"
+	"fmt"
+	"gorm.io/gorm/schema"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//DefaultTimeLayouts are the layouts filter values of time columns are parsed with, tried in order
+var DefaultTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+//GormFilterValueCoercer converts a single filter value to the type of the filtered column
+type GormFilterValueCoercer func(value string) (interface{}, error)
+
+//GormTypedFilter is implemented by filters whose values are coerced to the Go type of the column they filter,
+//CoerceValue receives the value as supplied, a string, a slice or a map of nested keys, and returns it in the shape Execute expects
+type GormTypedFilter interface {
+	GormAllowedFilter
+	Column() string
+	CoerceValue(value interface{}, coerce GormFilterValueCoercer) (interface{}, error)
+}
+
+var (
+	scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+	timeType    = reflect.TypeOf(time.Time{})
+)
+
+//filterOptions exposes the filter values coerced to the types of their columns to the filters
+type filterOptions struct {
+	OptionsInterface
+	filters    map[string]interface{}
+	rawFilters map[string]interface{}
+}
+
+func (f filterOptions) GetFilters() map[string]interface{} {
+	return f.filters
+}
+
+func (f filterOptions) GetRawFilters() map[string]interface{} {
+	return f.rawFilters
+}
+
+//TimeLayouts sets the layouts filter values of time columns are parsed with, defaults to DefaultTimeLayouts
+func (g *GormAdapter) TimeLayouts(layouts ...string) *GormAdapter {
+	g.timeLayouts = layouts
+	return g
+}
+
+//coerceFilters converts the raw values of the whitelisted typed filters using the schema of the model,
+//values of columns that are not found in the schema, e.g. on adapters built with db.Table, go through guessValue
+func (g *GormAdapter) coerceFilters(instance OptionsInterface, validation *QueryValidationError) OptionsInterface {
+	rawGetter, ok := instance.(interface{ GetRawFilters() map[string]interface{} })
+	if !ok || len(instance.GetFilters()) == 0 {
+		return instance
+	}
+	rawFilters := rawGetter.GetRawFilters()
+
+	filters := make(map[string]interface{}, len(instance.GetFilters()))
+	for key, value := range instance.GetFilters() {
+		filters[key] = value
+	}
+
+	for _, filter := range g.typedFilters(instance) {
+		field := g.lookUpField(filter.Column())
+		for _, key := range filter.Keys() {
+			raw, supplied := rawFilters[key]
+			if !supplied {
+				continue
+			}
+
+			value, err := filter.CoerceValue(raw, func(value string) (interface{}, error) {
+				if field == nil {
+					return guessValue(value), nil
+				}
+				return g.coerceValue(field, value)
+			})
+			if err != nil {
+				validation.Add(&QueryParameterError{
+					Code:      ErrorCodeInvalidFilterValue,
+					Parameter: fmt.Sprintf("filter[%s]", key),
+					Value:     fmt.Sprint(raw),
+					Detail:    fmt.Sprintf("invalid value for filter %s, %s", key, err.Error()),
+					Err:       ErrInvalidFilterValue,
+				}, ErrorCodeInvalidFilterValue, fmt.Sprintf("filter[%s]", key))
+				continue
+			}
+			filters[key] = value
+		}
+	}
+
+	return filterOptions{OptionsInterface: instance, filters: filters, rawFilters: rawFilters}
+}
+
+//typedFilters returns the filters whose values are coerced, without a white list every supplied filter is applied as
+//a partial filter so those are coerced as well
+func (g *GormAdapter) typedFilters(instance OptionsInterface) []GormTypedFilter {
+	var typedFilters []GormTypedFilter
+	if len(g.filtersWhitelist) == 0 {
+		for key := range instance.GetFilters() {
+			typedFilters = append(typedFilters, NewGormAllowedFilterSearch(key))
+		}
+		return typedFilters
+	}
+
+	for _, entry := range g.filtersWhitelist {
+		if filter, ok := entry.(GormTypedFilter); ok {
+			typedFilters = append(typedFilters, filter)
+		} else if key, isString := entry.(string); isString {
+			typedFilters = append(typedFilters, NewGormAllowedFilterSearch(key))
+		}
+	}
+	return typedFilters
+}
+
+//guessValue parses values of columns without a schema field like the url parser does, unless the guess would change
+//the value, e.g. the leading zero of 01234, in which case the value is kept as supplied
+func guessValue(value string) interface{} {
+	guessed := simpleParseString(value)
+	if fmt.Sprint(guessed) != value {
+		return value
+	}
+	return guessed
+}
+
+//lookUpField finds the schema field of the column, following relationships for columns such as author.country.code
+func (g *GormAdapter) lookUpField(columnName string) *schema.Field {
+	currentSchema := getSchema(g.db)
+	if currentSchema == nil {
+		return nil
+	}
+
+	parts := strings.Split(columnName, ".")
+	for index, part := range parts[:len(parts)-1] {
+		relationship := findRelationship(currentSchema, part)
+		if relationship == nil {
+			//a column qualified with the table name of the model
+			if index == 0 && len(parts) == 2 && part == currentSchema.Table {
+				break
+			}
+			return nil
+		}
+		currentSchema = relationship.FieldSchema
+	}
+	return currentSchema.LookUpField(parts[len(parts)-1])
+}
+
+//coerceValue parses the value into the Go type of the field, sql.Scanner implementations such as uuids and decimals
+//scan the string themselves
+func (g *GormAdapter) coerceValue(field *schema.Field, value string) (interface{}, error) {
+	fieldType := field.FieldType
+	for fieldType.Kind() == reflect.Ptr {
+		fieldType = fieldType.Elem()
+	}
+
+	if fieldType.ConvertibleTo(timeType) && fieldType.Kind() == reflect.Struct {
+		return g.parseTime(value)
+	}
+
+	if reflect.PtrTo(fieldType).Implements(scannerType) {
+		scanned := reflect.New(fieldType)
+		if err := scanned.Interface().(sql.Scanner).Scan(value); err != nil {
+			return nil, err
+		}
+		return scanned.Elem().Interface(), nil
+	}
+
+	coerced := reflect.New(fieldType).Elem()
+	switch fieldType.Kind() {
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(strings.TrimSpace(value))
+		if err != nil {
+			return nil, err
+		}
+		coerced.SetBool(parsed)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		parsed, err := strconv.ParseInt(strings.TrimSpace(value), 10, fieldType.Bits())
+		if err != nil {
+			return nil, err
+		}
+		coerced.SetInt(parsed)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		parsed, err := strconv.ParseUint(strings.TrimSpace(value), 10, fieldType.Bits())
+		if err != nil {
+			return nil, err
+		}
+		coerced.SetUint(parsed)
+	case reflect.Float32, reflect.Float64:
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(value), fieldType.Bits())
+		if err != nil {
+			return nil, err
+		}
+		coerced.SetFloat(parsed)
+	default:
+		return value, nil
+	}
+	return coerced.Interface(), nil
+}
+
+func (g *GormAdapter) parseTime(value string) (time.Time, error) {
+	layouts := g.timeLayouts
+	if len(layouts) == 0 {
+		layouts = DefaultTimeLayouts
+	}
+
+	for _, layout := range layouts {
+		if parsed, err := time.Parse(layout, strings.TrimSpace(value)); err == nil {
+			return parsed, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%s does not match the time layouts %s", value, strings.Join(layouts, ", "))
+}
+
+//coerceValues applies the coercer to a single value or to each value of a list
+func coerceValues(value interface{}, coerce GormFilterValueCoercer) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		return coerce(v)
+	case []interface{}:
+		values := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			coerced, err := coerceValues(item, coerce)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, coerced)
+		}
+		return values, nil
+	case map[string]interface{}:
+		return nil, fmt.Errorf("nested keys are not supported")
+	default:
+		return v, nil
+	}
+}
@@ -0,0 +1,215 @@
+package querybuilder_test
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/akacokafor/gorm-query-builder/pkg/querybuilder"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+//coercedSku is a custom column type scanned from and stored as an upper case string
+type coercedSku string
+
+func (s *coercedSku) Scan(value interface{}) error {
+	raw, ok := value.(string)
+	if !ok || !strings.HasPrefix(strings.ToUpper(raw), "SKU-") {
+		return fmt.Errorf("invalid sku %v", value)
+	}
+	*s = coercedSku(strings.ToUpper(raw))
+	return nil
+}
+
+func (s coercedSku) Value() (driver.Value, error) {
+	return string(s), nil
+}
+
+type coercedProduct struct {
+	ID         uint
+	Zip        string
+	Code       string
+	Price      float64
+	Stock      int64
+	Active     bool
+	Sku        coercedSku
+	ReleasedAt time.Time
+}
+
+func openCoercionDB(t *testing.T) *gorm.DB {
+	products := []coercedProduct{
+		{Zip: "01234", Code: "true", Price: 9.99, Stock: 3, Active: true, Sku: "SKU-1", ReleasedAt: time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)},
+		{Zip: "1234", Code: "false", Price: 19.5, Stock: 0, Active: false, Sku: "SKU-2", ReleasedAt: time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC)},
+		{Zip: "56789", Code: "abc", Price: 100, Stock: 12, Active: true, Sku: "SKU-3", ReleasedAt: time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)},
+	}
+	return openSeededDB(t, []interface{}{&coercedProduct{}}, &products)
+}
+
+func TestGormAdapter_FilterCoercion(t *testing.T) {
+	db := openCoercionDB(t)
+
+	tests := []struct {
+		name        string
+		filters     []interface{}
+		timeLayouts []string
+		url         string
+		wantErr     error
+		expected    []uint
+	}{
+		{
+			name:     "Should keep leading zeros of string columns",
+			filters:  []interface{}{querybuilder.NewGormAllowedFilterExact("zip")},
+			url:      "https://example.com?filter[zip]=01234",
+			expected: []uint{1},
+		},
+		{
+			name:     "Should not turn values of string columns into booleans",
+			filters:  []interface{}{querybuilder.NewGormAllowedFilterExact("code")},
+			url:      "https://example.com?filter[code]=true",
+			expected: []uint{1},
+		},
+		{
+			name:     "Should keep leading zeros of partial filters",
+			filters:  []interface{}{"zip"},
+			url:      "https://example.com?filter[zip]=0123",
+			expected: []uint{1},
+		},
+		{
+			name:     "Should keep leading zeros when no filters are white listed",
+			url:      "https://example.com?filter[zip]=01234",
+			expected: []uint{1},
+		},
+		{
+			name:     "Should coerce floats",
+			filters:  []interface{}{querybuilder.NewGormAllowedFilterOperator("price")},
+			url:      "https://example.com?filter[price][gte]=19.5",
+			expected: []uint{2, 3},
+		},
+		{
+			name:     "Should coerce the operands of prefixed operator values",
+			filters:  []interface{}{querybuilder.NewGormAllowedFilterOperator("price")},
+			url:      "https://example.com?filter[price]=lt:10.5",
+			expected: []uint{1},
+		},
+		{
+			name:     "Should coerce lists of integers",
+			filters:  []interface{}{querybuilder.NewGormAllowedFilterExact("stock")},
+			url:      "https://example.com?filter[stock]=0,12",
+			expected: []uint{2, 3},
+		},
+		{
+			name:     "Should coerce booleans",
+			filters:  []interface{}{querybuilder.NewGormAllowedFilterExact("active")},
+			url:      "https://example.com?filter[active]=false",
+			expected: []uint{2},
+		},
+		{
+			name:     "Should coerce dates",
+			filters:  []interface{}{querybuilder.NewGormAllowedFilterOperatorAlias("released", "released_at")},
+			url:      "https://example.com?filter[released][between]=2024-01-01,2024-02-28",
+			expected: []uint{1, 2},
+		},
+		{
+			name:        "Should coerce dates with custom layouts",
+			filters:     []interface{}{querybuilder.NewGormAllowedFilterOperatorAlias("released", "released_at")},
+			timeLayouts: []string{"02/01/2006"},
+			url:         "https://example.com?filter[released][gt]=01/03/2024",
+			expected:    []uint{3},
+		},
+		{
+			name:     "Should scan values of sql.Scanner columns",
+			filters:  []interface{}{querybuilder.NewGormAllowedFilterExact("sku")},
+			url:      "https://example.com?filter[sku]=sku-2",
+			expected: []uint{2},
+		},
+		{
+			name:    "Should reject values that can not be scanned",
+			filters: []interface{}{querybuilder.NewGormAllowedFilterExact("sku")},
+			url:     "https://example.com?filter[sku]=2",
+			wantErr: querybuilder.ErrInvalidFilterValue,
+		},
+		{
+			name:    "Should reject values that are not numbers",
+			filters: []interface{}{querybuilder.NewGormAllowedFilterOperator("price")},
+			url:     "https://example.com?filter[price][gt]=cheap",
+			wantErr: querybuilder.ErrInvalidFilterValue,
+		},
+		{
+			name:    "Should reject values that do not match a time layout",
+			filters: []interface{}{querybuilder.NewGormAllowedFilterOperatorAlias("released", "released_at")},
+			url:     "https://example.com?filter[released][gt]=yesterday",
+			wantErr: querybuilder.ErrInvalidFilterValue,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adapter := querybuilder.NewGormAdapter(db.Model(&coercedProduct{})).
+				AllowedFilters(tt.filters).
+				AllowedSorts([]interface{}{"id"}).
+				DefaultSort(querybuilder.Sort{Name: "id", Ascending: true})
+			if len(tt.timeLayouts) > 0 {
+				adapter.TimeLayouts(tt.timeLayouts...)
+			}
+
+			got, err := adapter.ExecuteOnUrl(tt.url)
+			if tt.wantErr != nil {
+				assert.True(t, errors.Is(err, tt.wantErr), "expected %v, got %v", tt.wantErr, err)
+				status, _ := querybuilder.ErrorResponse(err)
+				assert.Equal(t, 400, status)
+				return
+			}
+			assert.Nil(t, err)
+
+			var products []coercedProduct
+			assert.Nil(t, got.Find(&products).Error)
+
+			var ids []uint
+			for _, product := range products {
+				ids = append(ids, product.ID)
+			}
+			assert.Equal(t, tt.expected, ids)
+		})
+	}
+}
+
+func TestGormAdapter_FilterCoercionWithoutSchema(t *testing.T) {
+	db := openCoercionDB(t)
+
+	tests := []struct {
+		name     string
+		url      string
+		expected []string
+	}{
+		{
+			name:     "Should keep leading zeros of tables without a model",
+			url:      "https://example.com?filter[zip]=01234",
+			expected: []string{"01234"},
+		},
+		{
+			name:     "Should still match values that parse as numbers",
+			url:      "https://example.com?filter[zip]=1234",
+			expected: []string{"1234"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := querybuilder.NewGormAdapter(db.Table("coerced_products")).
+				AllowedFilters([]interface{}{querybuilder.NewGormAllowedFilterExact("zip")}).
+				ExecuteOnUrl(tt.url)
+			assert.Nil(t, err)
+
+			var products []coercedProduct
+			assert.Nil(t, got.Find(&products).Error)
+
+			var zips []string
+			for _, product := range products {
+				zips = append(zips, product.Zip)
+			}
+			assert.Equal(t, tt.expected, zips)
+		})
+	}
+}
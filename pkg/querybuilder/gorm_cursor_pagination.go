@@ -0,0 +1,332 @@
+package querybuilder
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+	"reflect"
+	"strings"
+)
+
+//ErrInvalidCursorDest is returned when the dest of CursorPaginateInto is not a non-nil pointer to a slice
+var ErrInvalidCursorDest = errors.New("cursor pagination requires a pointer to a slice")
+
+const (
+	cursorDirectionNext = "next"
+	cursorDirectionPrev = "prev"
+	defaultTieBreaker   = "id"
+)
+
+type CursorPaginationMeta struct {
+	PerPage    int    `json:"per_page"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+}
+
+//CursorPaginationResult holds a page of items along with the opaque cursors of the neighbouring pages
+type CursorPaginationResult struct {
+	Items                interface{} `json:"data"`
+	CursorPaginationMeta `json:"meta"`
+	//Links is only set when the query was applied with an url, e.g. with CursorPaginateIntoOnUrl
+	Links *PaginationLinks `json:"links,omitempty"`
+}
+
+//cursor is the decoded form of the cursor query parameter, Values holds the sort values of the boundary row
+type cursor struct {
+	Direction string            `json:"d"`
+	Values    []json.RawMessage `json:"v"`
+}
+
+func (c cursor) encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(value string) (*cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("cursor decode error: %v, %w", err, ErrInvalidCursorQuery)
+	}
+
+	var c cursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("cursor decode error: %v, %w", err, ErrInvalidCursorQuery)
+	}
+
+	if c.Direction != cursorDirectionNext && c.Direction != cursorDirectionPrev {
+		return nil, fmt.Errorf("invalid cursor direction %s, %w", c.Direction, ErrInvalidCursorQuery)
+	}
+	return &c, nil
+}
+
+//CursorPaginateInto paginates the query with a keyset derived from the sorts instead of an offset,
+//loads the page into dest, which must be a pointer to a slice, and returns the cursors of the neighbouring pages
+func (g *GormAdapter) CursorPaginateInto(optionsInstance OptionsInterface, dest interface{}) (*CursorPaginationResult, error) {
+	destValue := reflect.ValueOf(dest)
+	if destValue.Kind() != reflect.Ptr || destValue.IsNil() || destValue.Elem().Kind() != reflect.Slice {
+		return nil, fmt.Errorf("invalid dest %T, %w", dest, ErrInvalidCursorDest)
+	}
+
+	g.defaultToPagination = true
+	g.options = optionsInstance
+
+	validatedOptions, err := g.validate(optionsInstance)
+	if err != nil {
+		return nil, err
+	}
+
+	sorts, err := g.getCursorSorts(optionsInstance)
+	if err != nil {
+		return nil, err
+	}
+
+	var current *cursor
+	if cursorGetter, ok := optionsInstance.(interface{ GetCursor() *string }); ok && cursorGetter.GetCursor() != nil {
+		if current, err = decodeCursor(*cursorGetter.GetCursor()); err != nil {
+			return nil, err
+		}
+		if len(current.Values) != len(sorts) {
+			return nil, fmt.Errorf("cursor does not match the sorts, %w", ErrInvalidCursorQuery)
+		}
+	}
+
+	keysetColumns := make([]string, 0, len(sorts))
+	for _, sort := range sorts {
+		keysetColumns = append(keysetColumns, sort.GetName())
+	}
+	applyFields := func(instance OptionsInterface) error {
+		return g.applyFieldsWith(instance, keysetColumns)
+	}
+
+	for _, apply := range []func(OptionsInterface) error{g.applyFilters, g.applyQuery, applyFields, g.applyIncludes} {
+		if err := apply(validatedOptions); err != nil {
+			return nil, err
+		}
+	}
+
+	backwards := current != nil && current.Direction == cursorDirectionPrev
+	if current != nil {
+		condition, err := g.keysetCondition(sorts, current, backwards, dest)
+		if err != nil {
+			return nil, err
+		}
+		g.db.Where(condition)
+	}
+
+	for _, sort := range sorts {
+		g.orderBy(Sort{Name: sort.GetName(), Ascending: sort.IsAscending() != backwards})
+	}
+
+	_, size, _ := g.getPagination(optionsInstance)
+	if err := g.db.Limit(size + 1).Find(dest).Error; err != nil {
+		return nil, err
+	}
+
+	items := reflect.ValueOf(dest).Elem()
+	hasMore := items.Len() > size
+	if hasMore {
+		items.Set(items.Slice(0, size))
+	}
+	if backwards {
+		swap := reflect.Swapper(items.Interface())
+		for i, j := 0, items.Len()-1; i < j; i, j = i+1, j-1 {
+			swap(i, j)
+		}
+	}
+
+	result := &CursorPaginationResult{Items: dest, CursorPaginationMeta: CursorPaginationMeta{PerPage: size}}
+	if items.Len() == 0 {
+		result.Links, err = g.cursorLinks(optionsInstance, size, "", "")
+		return result, err
+	}
+
+	if hasMore || backwards {
+		if result.NextCursor, err = g.encodeCursor(cursorDirectionNext, sorts, dest, items.Index(items.Len()-1)); err != nil {
+			return nil, err
+		}
+	}
+	if current != nil && (hasMore || !backwards) {
+		if result.PrevCursor, err = g.encodeCursor(cursorDirectionPrev, sorts, dest, items.Index(0)); err != nil {
+			return nil, err
+		}
+	}
+
+	if result.Links, err = g.cursorLinks(optionsInstance, size, result.NextCursor, result.PrevCursor); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+//CursorPaginateIntoOnUrl parses the url and cursor paginates the query into dest, the result links to the neighbouring pages
+func (g *GormAdapter) CursorPaginateIntoOnUrl(url string, dest interface{}) (*CursorPaginationResult, error) {
+	optionsInstance, err := g.parseUrl(url)
+	if err != nil {
+		return nil, err
+	}
+	g.requestUrl = url
+
+	return g.CursorPaginateInto(optionsInstance, dest)
+}
+
+//getCursorSorts returns the active sorts followed by the tie breaker, only column sorts can be used to build a keyset
+func (g *GormAdapter) getCursorSorts(instance OptionsInterface) ([]Sortable, error) {
+	sortableList := instance.GetSort()
+	if g.defaultSort != nil && len(sortableList) == 0 {
+		sortableList = append(sortableList, g.defaultSort)
+	}
+
+	tieBreaker := g.cursorTieBreaker
+	if tieBreaker == "" {
+		tieBreaker = defaultTieBreaker
+	}
+
+	var sorts []Sortable
+	hasTieBreaker := false
+	for _, sort := range sortableList {
+		column, ok := g.sortColumn(sort.GetName())
+		if !ok {
+			return nil, fmt.Errorf("sort %s cannot be used with cursor pagination, %w", sort.GetName(), ErrInvalidCursorQuery)
+		}
+		if column == tieBreaker {
+			hasTieBreaker = true
+		}
+		sorts = append(sorts, Sort{Name: column, Ascending: sort.IsAscending()})
+	}
+
+	if !hasTieBreaker {
+		sorts = append(sorts, Sort{Name: tieBreaker, Ascending: true})
+	}
+	return sorts, nil
+}
+
+//sortColumn resolves the column a sort orders by, ok is false for custom sorts which cannot be turned into a keyset
+func (g *GormAdapter) sortColumn(name string) (column string, ok bool) {
+	for _, entry := range g.sortWhitelist {
+		if _k, isString := entry.(string); isString && _k == name {
+			return name, true
+		}
+	}
+	for _, entry := range g.sortWhitelist {
+		if op, isAllowedSort := entry.(GormAllowedSort); isAllowedSort {
+			for _, _k := range op.Names() {
+				if _k != name {
+					continue
+				}
+				if alias, isAlias := op.(*GormAllowedSortAlias); isAlias {
+					return alias.ColumnName(), true
+				}
+				return "", false
+			}
+		}
+	}
+	return name, true
+}
+
+//keysetCondition builds (a > ?) OR (a = ? AND b < ?) OR ... for the sorts, flipping the comparisons when paging backwards
+func (g *GormAdapter) keysetCondition(sorts []Sortable, current *cursor, backwards bool, dest interface{}) (clause.Expression, error) {
+	values, err := g.decodeCursorValues(sorts, current, dest)
+	if err != nil {
+		return nil, err
+	}
+
+	var conditions []clause.Expression
+	for index, sort := range sorts {
+		var group []clause.Expression
+		for previous := 0; previous < index; previous++ {
+			group = append(group, clause.Eq{Column: clause.Column{Name: sorts[previous].GetName()}, Value: values[previous]})
+		}
+
+		operator := ">"
+		if sort.IsAscending() == backwards {
+			operator = "<"
+		}
+		group = append(group, clause.Expr{
+			SQL:  fmt.Sprintf("? %s ?", operator),
+			Vars: []interface{}{clause.Column{Name: sort.GetName()}, values[index]},
+		})
+		conditions = append(conditions, clause.And(group...))
+	}
+
+	return anyOf(conditions), nil
+}
+
+//decodeCursorValues decodes the cursor values into the types of the matching fields of dest, when dest holds structs
+func (g *GormAdapter) decodeCursorValues(sorts []Sortable, current *cursor, dest interface{}) ([]interface{}, error) {
+	fieldSchema := g.parseDestSchema(dest)
+
+	values := make([]interface{}, len(sorts))
+	for index, sort := range sorts {
+		target := reflect.New(reflect.TypeOf((*interface{})(nil)).Elem())
+		if fieldSchema != nil {
+			if field := fieldSchema.LookUpField(unqualifiedColumn(sort.GetName())); field != nil {
+				target = reflect.New(field.FieldType)
+			}
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader(current.Values[index]))
+		decoder.UseNumber()
+		if err := decoder.Decode(target.Interface()); err != nil {
+			return nil, fmt.Errorf("cursor decode error: %v, %w", err, ErrInvalidCursorQuery)
+		}
+		values[index] = target.Elem().Interface()
+	}
+	return values, nil
+}
+
+func (g *GormAdapter) encodeCursor(direction string, sorts []Sortable, dest interface{}, item reflect.Value) (string, error) {
+	fieldSchema := g.parseDestSchema(dest)
+	item = reflect.Indirect(item)
+	if item.Kind() == reflect.Interface {
+		item = reflect.Indirect(item.Elem())
+	}
+
+	c := cursor{Direction: direction}
+	for _, sort := range sorts {
+		column := unqualifiedColumn(sort.GetName())
+
+		var value interface{}
+		switch item.Kind() {
+		case reflect.Map:
+			mapValue := item.MapIndex(reflect.ValueOf(column))
+			if !mapValue.IsValid() {
+				return "", fmt.Errorf("cursor column %s was not loaded, %w", column, ErrInvalidCursorQuery)
+			}
+			value = mapValue.Interface()
+		case reflect.Struct:
+			if fieldSchema == nil {
+				return "", fmt.Errorf("cursor column %s was not loaded, %w", column, ErrInvalidCursorQuery)
+			}
+			field := fieldSchema.LookUpField(column)
+			if field == nil || !isColumnSelected(g.db, column) {
+				return "", fmt.Errorf("cursor column %s was not loaded, %w", column, ErrInvalidCursorQuery)
+			}
+			value, _ = field.ValueOf(item)
+		default:
+			return "", fmt.Errorf("cursor pagination requires a slice of structs or maps, %w", ErrInvalidCursorQuery)
+		}
+
+		data, err := json.Marshal(value)
+		if err != nil {
+			return "", err
+		}
+		c.Values = append(c.Values, data)
+	}
+	return c.encode(), nil
+}
+
+func (g *GormAdapter) parseDestSchema(dest interface{}) *schema.Schema {
+	stmt := &gorm.Statement{DB: g.db}
+	if err := stmt.Parse(dest); err != nil {
+		return nil
+	}
+	return stmt.Schema
+}
+
+func unqualifiedColumn(column string) string {
+	return column[strings.LastIndex(column, ".")+1:]
+}
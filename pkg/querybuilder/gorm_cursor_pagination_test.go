@@ -0,0 +1,165 @@
+package querybuilder_test
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/akacokafor/gorm-query-builder/pkg/querybuilder"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestGormAdapter_CursorPaginateInto(t *testing.T) {
+	db := openPaginationDB(t, 45)
+	newAdapter := func() *querybuilder.GormAdapter {
+		return querybuilder.NewGormAdapter(db.Model(&paginatedUser{})).
+			AllowedFilters([]interface{}{querybuilder.NewGormAllowedFilterExact("status")}).
+			AllowedSorts([]interface{}{
+				"id",
+				"name",
+				"status",
+				querybuilder.NewGormAllowedSortAlias("label", "name"),
+				querybuilder.NewGormAllowedSortCustom("name_length", func(db *gorm.DB, ascending bool, propertyName string) error {
+					return nil
+				}),
+			})
+	}
+
+	var expected []paginatedUser
+	db.Order("status ASC").Order("name DESC").Order("id ASC").Find(&expected)
+
+	t.Run("Should walk forward through every row exactly once with mixed sort directions", func(t *testing.T) {
+		var seen []paginatedUser
+		var pages []*querybuilder.CursorPaginationResult
+		cursor := ""
+		for {
+			var users []paginatedUser
+			target := "https://example.com?sort=status,-name&size=10"
+			if cursor != "" {
+				target = fmt.Sprintf("%s&cursor=%s", target, url.QueryEscape(cursor))
+			}
+			result, err := newAdapter().CursorPaginateIntoOnUrl(target, &users)
+			assert.Nil(t, err)
+			seen = append(seen, users...)
+			pages = append(pages, result)
+			if result.NextCursor == "" {
+				break
+			}
+			cursor = result.NextCursor
+		}
+
+		assert.Len(t, pages, 5)
+		assert.Equal(t, expected, seen)
+		assert.Empty(t, pages[0].PrevCursor)
+		assert.NotEmpty(t, pages[4].PrevCursor)
+
+		var previous []paginatedUser
+		result, err := newAdapter().CursorPaginateIntoOnUrl(
+			fmt.Sprintf("https://example.com?sort=status,-name&size=10&cursor=%s", url.QueryEscape(pages[2].PrevCursor)),
+			&previous,
+		)
+		assert.Nil(t, err)
+		assert.Equal(t, expected[10:20], previous)
+		assert.NotEmpty(t, result.PrevCursor)
+		assert.NotEmpty(t, result.NextCursor)
+
+		var first []paginatedUser
+		result, err = newAdapter().CursorPaginateIntoOnUrl(
+			fmt.Sprintf("https://example.com?sort=status,-name&size=10&cursor=%s", url.QueryEscape(pages[1].PrevCursor)),
+			&first,
+		)
+		assert.Nil(t, err)
+		assert.Equal(t, expected[:10], first)
+		assert.Empty(t, result.PrevCursor)
+	})
+
+	t.Run("Should use the tie breaker when no sort is supplied and paginate maps", func(t *testing.T) {
+		var rows []map[string]interface{}
+		result, err := newAdapter().CursorPaginateIntoOnUrl("https://example.com?filter[status]=open&size=20", &rows)
+		assert.Nil(t, err)
+		assert.Len(t, rows, 20)
+		assert.NotEmpty(t, result.NextCursor)
+
+		var next []map[string]interface{}
+		_, err = newAdapter().CursorPaginateIntoOnUrl(
+			fmt.Sprintf("https://example.com?filter[status]=open&size=20&cursor=%s", url.QueryEscape(result.NextCursor)),
+			&next,
+		)
+		assert.Nil(t, err)
+		assert.Len(t, next, 3)
+		assert.EqualValues(t, 41, next[0]["id"])
+	})
+
+	t.Run("Should build the keyset from the column of aliased sorts", func(t *testing.T) {
+		var users []paginatedUser
+		result, err := newAdapter().CursorPaginateIntoOnUrl("https://example.com?sort=-label&size=5", &users)
+		assert.Nil(t, err)
+		assert.Equal(t, "user 45", users[0].Name)
+
+		var next []paginatedUser
+		_, err = newAdapter().CursorPaginateIntoOnUrl(
+			fmt.Sprintf("https://example.com?sort=-label&size=5&cursor=%s", url.QueryEscape(result.NextCursor)),
+			&next,
+		)
+		assert.Nil(t, err)
+		assert.Equal(t, "user 40", next[0].Name)
+	})
+
+	t.Run("Should load the keyset columns left out of sparse fieldsets", func(t *testing.T) {
+		var seen []paginatedUser
+		cursor := ""
+		for pages := 0; pages < 10; pages++ {
+			var users []paginatedUser
+			target := "https://example.com?sort=status,-name&size=10&fields[paginated_users]=name"
+			if cursor != "" {
+				target = fmt.Sprintf("%s&cursor=%s", target, url.QueryEscape(cursor))
+			}
+			result, err := newAdapter().CursorPaginateIntoOnUrl(target, &users)
+			if !assert.Nil(t, err) {
+				return
+			}
+			seen = append(seen, users...)
+			if result.NextCursor == "" {
+				break
+			}
+			cursor = result.NextCursor
+		}
+
+		assert.Equal(t, expected, seen)
+	})
+
+	t.Run("Should reject keyset columns the query does not load", func(t *testing.T) {
+		var users []paginatedUser
+		_, err := querybuilder.NewGormAdapter(db.Model(&paginatedUser{}).Select("name")).
+			AllowedSorts([]interface{}{"status"}).
+			CursorPaginateIntoOnUrl("https://example.com?sort=status&size=10", &users)
+		assert.ErrorIs(t, err, querybuilder.ErrInvalidCursorQuery)
+	})
+
+	t.Run("Should reject custom sorts and malformed cursors", func(t *testing.T) {
+		var users []paginatedUser
+		_, err := newAdapter().CursorPaginateIntoOnUrl("https://example.com?sort=name_length", &users)
+		assert.True(t, errors.Is(err, querybuilder.ErrInvalidCursorQuery))
+
+		_, err = newAdapter().CursorPaginateIntoOnUrl("https://example.com?cursor=not-a-cursor", &users)
+		assert.True(t, errors.Is(err, querybuilder.ErrInvalidCursorQuery))
+	})
+
+	t.Run("Should reject a dest that is not a pointer to a slice", func(t *testing.T) {
+		var nilUsers *[]paginatedUser
+		for name, dest := range map[string]interface{}{
+			"slice value":       []paginatedUser{},
+			"pointer to struct": &paginatedUser{},
+			"nil":               nil,
+			"nil pointer":       nilUsers,
+			"pointer to a map":  &map[string]interface{}{},
+		} {
+			assert.NotPanics(t, func() {
+				_, err := newAdapter().CursorPaginateIntoOnUrl("https://example.com?size=5", dest)
+				assert.ErrorIs(t, err, querybuilder.ErrInvalidCursorDest, name)
+			}, name)
+		}
+	})
+}
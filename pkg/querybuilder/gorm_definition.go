@@ -0,0 +1,224 @@
+package querybuilder
+
+import (
+	"gorm.io/gorm"
+)
+
+//QueryDefinition holds the whitelists and defaults of a query, it is built once, e.g. at startup, and applied to
+//a fresh session of the db on every call so it can be shared between requests and goroutines.
+//The setters return a modified copy and never change the definition they are called on
+type QueryDefinition struct {
+	queryConfig
+}
+
+//NewQueryDefinition creates an empty definition, without whitelists every filter, sort and field is accepted and includes are rejected
+func NewQueryDefinition() *QueryDefinition {
+	return &QueryDefinition{}
+}
+
+//NewQueryDefinitionFor creates a definition with the whitelists built from the qb struct tags of the model,
+//see NewGormAdapterFor
+func NewQueryDefinitionFor(db *gorm.DB, model interface{}) (*QueryDefinition, error) {
+	adapter, err := NewGormAdapterFor(db, model)
+	if err != nil {
+		return nil, err
+	}
+	return adapter.Definition(), nil
+}
+
+//Model sets the model the definition queries, it is applied with db.Model when the db does not have one
+func (d *QueryDefinition) Model(model interface{}) *QueryDefinition {
+	c := d.clone()
+	c.model = model
+	return c
+}
+
+//AllowedFilters white lists only the acceptable filters that can be applied from the query parameters
+func (d *QueryDefinition) AllowedFilters(filtersWhitelist []interface{}) *QueryDefinition {
+	c := d.clone()
+	c.filtersWhitelist = copyWhitelist(filtersWhitelist)
+	return c
+}
+
+//AllowedIncludes white lists only the acceptable includes that can be applied from the query parameters
+func (d *QueryDefinition) AllowedIncludes(includesWhitelist []interface{}) *QueryDefinition {
+	c := d.clone()
+	c.includesWhitelist = copyWhitelist(includesWhitelist)
+	return c
+}
+
+//AllowUnlistedIncludes preloads any relationship requested by the client when no includes are white listed
+func (d *QueryDefinition) AllowUnlistedIncludes(allow bool) *QueryDefinition {
+	c := d.clone()
+	c.unlistedIncludes = allow
+	return c
+}
+
+//MaxIncludeDepth limits the nesting of includes, e.g. posts.comments has a depth of 2, 0 disables the limit
+func (d *QueryDefinition) MaxIncludeDepth(depth int) *QueryDefinition {
+	c := d.clone()
+	c.maxIncludeDepth = depth
+	return c
+}
+
+//AllowedFields white lists only the acceptable fields that can be selected from the query parameters
+func (d *QueryDefinition) AllowedFields(fieldsWhiteList []interface{}) *QueryDefinition {
+	c := d.clone()
+	c.fieldsWhiteList = copyWhitelist(fieldsWhiteList)
+	return c
+}
+
+//AllowedSorts white lists only the acceptable sort columns that can be applied from the query parameters
+func (d *QueryDefinition) AllowedSorts(sortWhitelist []interface{}) *QueryDefinition {
+	c := d.clone()
+	c.sortWhitelist = copyWhitelist(sortWhitelist)
+	return c
+}
+
+//DefaultSort sets the default sort to apply on query
+func (d *QueryDefinition) DefaultSort(defaultSort Sortable) *QueryDefinition {
+	c := d.clone()
+	c.defaultSort = defaultSort
+	return c
+}
+
+//CursorTieBreaker sets the unique column appended to the sorts in cursor pagination, defaults to id
+func (d *QueryDefinition) CursorTieBreaker(column string) *QueryDefinition {
+	c := d.clone()
+	c.cursorTieBreaker = column
+	return c
+}
+
+//TimeLayouts sets the layouts filter values of time columns are parsed with, defaults to DefaultTimeLayouts
+func (d *QueryDefinition) TimeLayouts(layouts ...string) *QueryDefinition {
+	c := d.clone()
+	c.timeLayouts = append([]string(nil), layouts...)
+	return c
+}
+
+//DefaultPage sets the page paginated queries start at when no page is requested, defaults to 1
+func (d *QueryDefinition) DefaultPage(page int) *QueryDefinition {
+	c := d.clone()
+	c.defaultPage = page
+	return c
+}
+
+//DefaultPageSize sets the size of a page when no size is requested, defaults to DefaultPageSize
+func (d *QueryDefinition) DefaultPageSize(size int) *QueryDefinition {
+	c := d.clone()
+	c.defaultPageSize = size
+	return c
+}
+
+//MaxPageSize limits the size a client can request, 0 disables the limit
+func (d *QueryDefinition) MaxPageSize(size int) *QueryDefinition {
+	c := d.clone()
+	c.maxPageSize = size
+	return c
+}
+
+//AllowedPageSizes white lists the sizes a client can request
+func (d *QueryDefinition) AllowedPageSizes(sizes ...int) *QueryDefinition {
+	c := d.clone()
+	c.allowedPageSizes = append([]int(nil), sizes...)
+	return c
+}
+
+//PageSizeMode sets whether invalid page sizes are clamped, the default, or rejected with ErrInvalidPageSize
+func (d *QueryDefinition) PageSizeMode(mode PageSizeMode) *QueryDefinition {
+	c := d.clone()
+	c.pageSizeMode = mode
+	return c
+}
+
+//AllowedSearch sets the columns the q parameter searches, columns of relationships are qualified with the relationship, e.g. author.name
+func (d *QueryDefinition) AllowedSearch(columns ...string) *QueryDefinition {
+	c := d.clone()
+	c.searchColumns = append([]string(nil), columns...)
+	return c
+}
+
+//SearchBackend sets how the q parameter is matched against the search columns, defaults to NewGormSearchLike
+func (d *QueryDefinition) SearchBackend(backend GormSearchBackend) *QueryDefinition {
+	c := d.clone()
+	c.searchBackend = backend
+	return c
+}
+
+//BaseUrl sets the public url the pagination links start with, see GormAdapter.BaseUrl
+func (d *QueryDefinition) BaseUrl(baseUrl string) *QueryDefinition {
+	c := d.clone()
+	c.baseUrl = baseUrl
+	return c
+}
+
+//FilterDelimiter sets the delimiter the *OnUrl methods split filter values on, see GormAdapter.FilterDelimiter
+func (d *QueryDefinition) FilterDelimiter(delimiter string) *QueryDefinition {
+	c := d.clone()
+	c.filterDelimiter = &delimiter
+	return c
+}
+
+//Adapter creates an adapter for a single call working on a fresh session of the db,
+//the statement of the db passed in is never modified
+func (d *QueryDefinition) Adapter(db *gorm.DB) *GormAdapter {
+	session := db.Session(&gorm.Session{})
+	if d.model != nil && session.Statement.Model == nil {
+		session = session.Model(d.model)
+	}
+	//Clauses without arguments returns an instance that later calls modify in place, as GormAdapter expects
+	return &GormAdapter{queryConfig: d.queryConfig.clone(), db: session.Clauses()}
+}
+
+//Apply applies the query parameters to a fresh session of the db and returns it
+func (d *QueryDefinition) Apply(db *gorm.DB, optionsInstance OptionsInterface) (*gorm.DB, error) {
+	return d.Adapter(db).Execute(optionsInstance)
+}
+
+//ApplyOnUrl parses the url and applies its query parameters to a fresh session of the db
+func (d *QueryDefinition) ApplyOnUrl(db *gorm.DB, url string) (*gorm.DB, error) {
+	return d.Adapter(db).ExecuteOnUrl(url)
+}
+
+//PaginateInto paginates a fresh session of the db and loads the page into dest, see GormAdapter.PaginateInto
+func (d *QueryDefinition) PaginateInto(db *gorm.DB, optionsInstance OptionsInterface, dest interface{}) (*PaginationResult, error) {
+	return d.Adapter(db).PaginateInto(optionsInstance, dest)
+}
+
+//PaginateIntoOnUrl parses the url and paginates a fresh session of the db into dest, see GormAdapter.PaginateIntoOnUrl
+func (d *QueryDefinition) PaginateIntoOnUrl(db *gorm.DB, url string, dest interface{}) (*PaginationResult, error) {
+	return d.Adapter(db).PaginateIntoOnUrl(url, dest)
+}
+
+//CursorPaginateInto paginates a fresh session of the db with a cursor, see GormAdapter.CursorPaginateInto
+func (d *QueryDefinition) CursorPaginateInto(db *gorm.DB, optionsInstance OptionsInterface, dest interface{}) (*CursorPaginationResult, error) {
+	return d.Adapter(db).CursorPaginateInto(optionsInstance, dest)
+}
+
+//CursorPaginateIntoOnUrl parses the url and paginates a fresh session of the db with a cursor, see GormAdapter.CursorPaginateIntoOnUrl
+func (d *QueryDefinition) CursorPaginateIntoOnUrl(db *gorm.DB, url string, dest interface{}) (*CursorPaginationResult, error) {
+	return d.Adapter(db).CursorPaginateIntoOnUrl(url, dest)
+}
+
+func (d *QueryDefinition) clone() *QueryDefinition {
+	return &QueryDefinition{queryConfig: d.queryConfig.clone()}
+}
+
+//clone copies the whitelists so that appending to the copy can not change the original
+func (c queryConfig) clone() queryConfig {
+	c.filtersWhitelist = copyWhitelist(c.filtersWhitelist)
+	c.sortWhitelist = copyWhitelist(c.sortWhitelist)
+	c.fieldsWhiteList = copyWhitelist(c.fieldsWhiteList)
+	c.includesWhitelist = copyWhitelist(c.includesWhitelist)
+	c.timeLayouts = append([]string(nil), c.timeLayouts...)
+	c.allowedPageSizes = append([]int(nil), c.allowedPageSizes...)
+	c.searchColumns = append([]string(nil), c.searchColumns...)
+	return c
+}
+
+func copyWhitelist(whitelist []interface{}) []interface{} {
+	if whitelist == nil {
+		return nil
+	}
+	return append([]interface{}(nil), whitelist...)
+}
@@ -0,0 +1,147 @@
+package querybuilder_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/akacokafor/gorm-query-builder/pkg/querybuilder"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryDefinition_Apply(t *testing.T) {
+	db := openPaginationDB(t, 12)
+
+	definition := querybuilder.NewQueryDefinition().
+		Model(&paginatedUser{}).
+		AllowedFilters([]interface{}{querybuilder.NewGormAllowedFilterExact("status"), querybuilder.NewGormAllowedFilterExact("id")}).
+		AllowedSorts([]interface{}{"id", "name"}).
+		DefaultSort(querybuilder.Sort{Name: "id", Ascending: true})
+
+	got, err := definition.ApplyOnUrl(db, "https://example.com?filter[status]=closed&sort=-id&page=1&size=2")
+	assert.Nil(t, err)
+
+	var users []paginatedUser
+	assert.Nil(t, got.Find(&users).Error)
+	assert.Len(t, users, 2)
+	assert.Equal(t, uint(12), users[0].ID)
+	assert.Equal(t, uint(10), users[1].ID)
+
+	var count int64
+	assert.Nil(t, db.Model(&paginatedUser{}).Count(&count).Error)
+	assert.Equal(t, int64(12), count, "the db passed to Apply must not be modified")
+
+	_, err = definition.ApplyOnUrl(db, "https://example.com?filter[name]=user")
+	assert.ErrorIs(t, err, querybuilder.ErrInvalidFilterQuery)
+}
+
+func TestQueryDefinition_SettersReturnCopies(t *testing.T) {
+	db := openPaginationDB(t, 3)
+
+	base := querybuilder.NewQueryDefinition().
+		Model(&paginatedUser{}).
+		AllowedSorts([]interface{}{"id"})
+	extended := base.AllowedSorts([]interface{}{"id", "name"})
+
+	_, err := base.ApplyOnUrl(db, "https://example.com?sort=name")
+	assert.ErrorIs(t, err, querybuilder.ErrInvalidSortQuery)
+
+	_, err = extended.ApplyOnUrl(db, "https://example.com?sort=name")
+	assert.Nil(t, err)
+}
+
+func TestQueryDefinition_ConcurrentApply(t *testing.T) {
+	db := openPaginationDB(t, 12)
+
+	definition := querybuilder.NewQueryDefinition().
+		Model(&paginatedUser{}).
+		AllowedFilters([]interface{}{querybuilder.NewGormAllowedFilterExact("id"), "name"}).
+		AllowedSorts([]interface{}{"id"}).
+		AllowedFields([]interface{}{"id", "name"}).
+		DefaultSort(querybuilder.Sort{Name: "id", Ascending: true})
+
+	var wg sync.WaitGroup
+	for i := 1; i <= 12; i++ {
+		options := mustParseUrl(t, fmt.Sprintf("https://example.com?filter[id]=%d&fields[paginated_users]=id,name", i))
+		wg.Add(1)
+		go func(id int, options *querybuilder.Options) {
+			defer wg.Done()
+
+			var users []paginatedUser
+			result, err := definition.PaginateInto(db, options, &users)
+			if !assert.Nil(t, err) {
+				return
+			}
+			assert.Equal(t, int64(1), result.Total)
+			if assert.Len(t, users, 1) {
+				assert.Equal(t, uint(id), users[0].ID)
+				assert.Equal(t, fmt.Sprintf("user %02d", id), users[0].Name)
+				assert.Empty(t, users[0].Status)
+			}
+		}(i, options)
+	}
+	wg.Wait()
+}
+
+func mustParseUrl(t *testing.T, url string) *querybuilder.Options {
+	options, err := querybuilder.ParseUrl(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return options
+}
+
+func TestGormAdapter_FilterDelimiter(t *testing.T) {
+	db := openPaginationDB(t, 6)
+
+	tests := []struct {
+		name      string
+		delimiter *string
+		url       string
+		expected  int64
+	}{
+		{
+			name:     "Should split filter values on commas by default",
+			url:      "https://example.com?filter[name]=user 01,user 02",
+			expected: 2,
+		},
+		{
+			name:      "Should split filter values on the configured delimiter",
+			delimiter: stringPointer("|"),
+			url:       "https://example.com?filter[name]=user 01|user 02|user 03",
+			expected:  3,
+		},
+		{
+			name:      "Should not split filter values with an empty delimiter",
+			delimiter: stringPointer(""),
+			url:       "https://example.com?filter[name]=user 01,user 02",
+			expected:  0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			definition := querybuilder.NewQueryDefinition().
+				Model(&paginatedUser{}).
+				AllowedFilters([]interface{}{querybuilder.NewGormAllowedFilterExact("name")})
+			adapter := querybuilder.NewGormAdapter(db.Model(&paginatedUser{})).
+				AllowedFilters([]interface{}{querybuilder.NewGormAllowedFilterExact("name")})
+			if tt.delimiter != nil {
+				definition = definition.FilterDelimiter(*tt.delimiter)
+				adapter.FilterDelimiter(*tt.delimiter)
+			}
+
+			var users []paginatedUser
+			result, err := adapter.PaginateIntoOnUrl(tt.url, &users)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expected, result.Total)
+
+			result, err = definition.PaginateIntoOnUrl(db, tt.url, &users)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expected, result.Total)
+		})
+	}
+}
+
+func stringPointer(value string) *string {
+	return &value
+}
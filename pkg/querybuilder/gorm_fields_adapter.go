@@ -0,0 +1,250 @@
+package querybuilder
+
+import (
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+	"sort"
+	"strings"
+)
+
+func (g *GormAdapter) validateFields(instance OptionsInterface, validation *QueryValidationError) error {
+	if len(g.fieldsWhiteList) == 0 {
+		return nil
+	}
+
+	for _, entry := range g.fieldsWhiteList {
+		if _, isString := entry.(string); !isString {
+			return errors.New("all fields must be string")
+		}
+	}
+
+	var allowed []string
+	for _, entry := range g.fieldsWhiteList {
+		allowed = append(allowed, entry.(string))
+	}
+
+	tableName := g.getTableName()
+	names := make([]string, 0, len(instance.GetFields()))
+	for name := range instance.GetFields() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		for _, field := range g.getRequestedFields(instance, name) {
+			if !g.isValidField(tableName, name, field) {
+				validation.Add(&QueryParameterError{
+					Code:      ErrorCodeInvalidField,
+					Parameter: fmt.Sprintf("fields[%s]", name),
+					Value:     field,
+					Allowed:   allowed,
+					Detail:    fmt.Sprintf("invalid field %s.%s", name, field),
+					Err:       ErrInvalidFieldQuery,
+				}, ErrorCodeInvalidField, fmt.Sprintf("fields[%s]", name))
+			}
+		}
+	}
+
+	return nil
+}
+
+func (g *GormAdapter) isValidField(tableName string, name string, field string) bool {
+	for _, entry := range g.fieldsWhiteList {
+		validKey, _ := entry.(string)
+		if validKey == fmt.Sprintf("%s.%s", name, field) {
+			return true
+		}
+		if name == tableName && validKey == field {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *GormAdapter) getRequestedFields(instance OptionsInterface, name string) []string {
+	return requestedFields(instance, name)
+}
+
+func requestedFields(instance OptionsInterface, name string) []string {
+	if name == "" {
+		return nil
+	}
+
+	var fields []string
+	for _, field := range instance.GetFields()[name] {
+		field = strings.TrimSpace(field)
+		if field != "" {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+//getTableName returns the name of the root table, which is the key sparse fieldsets of the root model are requested with
+func (g *GormAdapter) getTableName() string {
+	if g.db.Statement.Table != "" {
+		return g.db.Statement.Table
+	}
+
+	if g.db.Statement.Model != nil {
+		if err := g.db.Statement.Parse(g.db.Statement.Model); err == nil {
+			return g.db.Statement.Table
+		}
+	}
+
+	return ""
+}
+
+func (g *GormAdapter) applyFields(instance OptionsInterface) error {
+	return g.applyFieldsWith(instance, nil)
+}
+
+//applyFieldsWith restricts the root table to the requested fields and the required columns, which are selected even
+//when the client left them out, e.g. the keyset columns of cursor pagination
+func (g *GormAdapter) applyFieldsWith(instance OptionsInterface, required []string) error {
+	fields := g.getRequestedFields(instance, g.getTableName())
+	if len(fields) > 0 {
+		if rootSchema := getSchema(g.db); rootSchema != nil {
+			fields = withColumns(fields, keyColumns(rootSchema, nil)...)
+		}
+		selectColumns(g.db, withColumns(fields, required...))
+	}
+	return nil
+}
+
+//keyColumns returns the primary keys of the schema and the columns its relationships, and the relationship it is
+//loaded through, are joined on, gorm needs them to attach preloaded rows to their parents
+func keyColumns(s *schema.Schema, through *schema.Relationship) []string {
+	var columns []string
+	for _, field := range s.PrimaryFields {
+		columns = append(columns, field.DBName)
+	}
+
+	relationships := make([]*schema.Relationship, 0, len(s.Relationships.Relations)+1)
+	if through != nil {
+		relationships = append(relationships, through)
+	}
+	names := make([]string, 0, len(s.Relationships.Relations))
+	for name := range s.Relationships.Relations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		relationships = append(relationships, s.Relationships.Relations[name])
+	}
+
+	for _, relationship := range relationships {
+		for _, reference := range relationship.References {
+			if reference.PrimaryKey != nil && reference.PrimaryKey.Schema == s && reference.PrimaryKey.DBName != "" {
+				columns = append(columns, reference.PrimaryKey.DBName)
+			}
+			if reference.ForeignKey != nil && reference.ForeignKey.Schema == s && reference.ForeignKey.DBName != "" {
+				columns = append(columns, reference.ForeignKey.DBName)
+			}
+		}
+	}
+	return columns
+}
+
+//lookUpRelationship follows the dotted association name, e.g. Posts.Comments, from the schema of the query
+func lookUpRelationship(db *gorm.DB, relationshipName string) *schema.Relationship {
+	currentSchema := getSchema(db)
+	if currentSchema == nil {
+		return nil
+	}
+
+	var relationship *schema.Relationship
+	for _, name := range strings.Split(relationshipName, ".") {
+		if relationship = findRelationship(currentSchema, name); relationship == nil {
+			return nil
+		}
+		currentSchema = relationship.FieldSchema
+	}
+	return relationship
+}
+
+//withColumns appends the columns that are not among the fields yet
+func withColumns(fields []string, columns ...string) []string {
+	selected := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		selected[unqualifiedColumn(field)] = true
+	}
+
+	result := append([]string{}, fields...)
+	for _, column := range columns {
+		if !selected[unqualifiedColumn(column)] {
+			selected[unqualifiedColumn(column)] = true
+			result = append(result, column)
+		}
+	}
+	return result
+}
+
+//isColumnSelected reports whether rows loaded by the query hold the column, which is the case unless the query selects
+//a list of columns without it
+func isColumnSelected(db *gorm.DB, column string) bool {
+	var selected []string
+	if selectClause, ok := db.Statement.Clauses["SELECT"].Expression.(clause.Select); ok {
+		for _, selectedColumn := range selectClause.Columns {
+			selected = append(selected, selectedColumn.Name)
+		}
+	}
+	for _, selects := range db.Statement.Selects {
+		selected = append(selected, strings.Split(selects, ",")...)
+	}
+	if len(selected) == 0 {
+		return true
+	}
+
+	for _, selectedColumn := range selected {
+		selectedColumn = strings.Trim(strings.TrimSpace(selectedColumn), "`\"")
+		if selectedColumn == "*" || strings.HasSuffix(selectedColumn, ".*") || unqualifiedColumn(selectedColumn) == column {
+			return true
+		}
+	}
+	return false
+}
+
+//selectColumns restricts the query to the given columns, quoted for the current dialect
+func selectColumns(db *gorm.DB, fields []string) *gorm.DB {
+	columns := make([]clause.Column, 0, len(fields))
+	for _, field := range fields {
+		columns = append(columns, clause.Column{Name: field})
+	}
+	return db.Clauses(clause.Select{Columns: columns})
+}
+
+//preload registers the relationship and preloads it, restricting the loaded columns when
+//sparse fields were requested for the supplied include
+func (g *GormAdapter) preload(suppliedInclude string, relationshipName string, instance OptionsInterface) {
+	g.addRelationship(relationshipName)
+	preloadRelationship(g.db, suppliedInclude, relationshipName, instance, nil)
+}
+
+//preloadRelationship preloads the relationship with the conditions of the callback, if any, and the sparse
+//fields requested for the include along with the keys the rows are attached with
+func preloadRelationship(db *gorm.DB, include string, relationshipName string, instance OptionsInterface, callback func(db *gorm.DB) *gorm.DB) {
+	fields := requestedFields(instance, include)
+	if len(fields) > 0 {
+		if relationship := lookUpRelationship(db, relationshipName); relationship != nil {
+			fields = withColumns(fields, keyColumns(relationship.FieldSchema, relationship)...)
+		}
+	}
+	if len(fields) == 0 && callback == nil {
+		db.Preload(relationshipName)
+		return
+	}
+
+	db.Preload(relationshipName, func(tx *gorm.DB) *gorm.DB {
+		if callback != nil {
+			tx = callback(tx)
+		}
+		if len(fields) > 0 {
+			tx = selectColumns(tx, fields)
+		}
+		return tx
+	})
+}
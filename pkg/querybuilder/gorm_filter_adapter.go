@@ -4,8 +4,8 @@ import (
 	"errors"
 	"fmt"
 	"gorm.io/gorm"
-	"log"
-	"reflect"
+	"gorm.io/gorm/clause"
+	"sort"
 )
 
 type GormAllowedFilter interface {
@@ -13,6 +13,12 @@ type GormAllowedFilter interface {
 	Execute(db *gorm.DB, options OptionsInterface) error
 }
 
+//GormValidatedFilter is implemented by filters that check their value before any filter is applied,
+//returning a *QueryParameterError describes the problem to the client
+type GormValidatedFilter interface {
+	Validate(options OptionsInterface) error
+}
+
 func (g *GormAdapter) isValidFilterKey(key string) bool {
 	filterKeys := g.getFilterKeys(g.filtersWhitelist)
 	for _, validKey := range filterKeys {
@@ -37,31 +43,61 @@ func (g *GormAdapter) getFilterKeys(whitelist []interface{}) []string {
 	return keys
 }
 
-func (g *GormAdapter) validateFilters(instance OptionsInterface) error {
+func (g *GormAdapter) validateFilters(instance OptionsInterface, validation *QueryValidationError) error {
 	if len(g.filtersWhitelist) == 0 {
+		for _, key := range sortedKeys(instance.GetFilters()) {
+			if err := rejectOperators(key, instance); err != nil {
+				validation.Add(err, ErrorCodeInvalidFilterOperator, "filter")
+			}
+		}
 		return nil
 	}
 
 	for _, entry := range g.filtersWhitelist {
-		log.Printf("entry = %s", reflect.TypeOf(entry).String())
 		_, isString := entry.(string)
 		_, isAllowedFilter := entry.(GormAllowedFilter)
-
-		log.Printf("entry = %s isString = %v, isAllowedFilter = %v", reflect.TypeOf(entry).String(), isString, isAllowedFilter)
 		if !isAllowedFilter && !isString {
 			return errors.New("all filters must be string or objects that implement GormAllowedFilter")
 		}
 	}
 
-	for key, _ := range instance.GetFilters() {
+	for _, key := range sortedKeys(instance.GetFilters()) {
 		if !g.isValidFilterKey(key) {
-			return fmt.Errorf("invalid filter key %s, %w", key, ErrInvalidFilterQuery)
+			validation.Add(&QueryParameterError{
+				Code:      ErrorCodeInvalidFilter,
+				Parameter: fmt.Sprintf("filter[%s]", key),
+				Allowed:   g.getFilterKeys(g.filtersWhitelist),
+				Detail:    fmt.Sprintf("invalid filter key %s", key),
+				Err:       ErrInvalidFilterQuery,
+			}, ErrorCodeInvalidFilter, fmt.Sprintf("filter[%s]", key))
+		}
+	}
+
+	for _, entry := range g.filtersWhitelist {
+		if key, isString := entry.(string); isString {
+			if err := rejectOperators(key, instance); err != nil {
+				validation.Add(err, ErrorCodeInvalidFilterOperator, "filter")
+			}
+		}
+		if validator, ok := entry.(GormValidatedFilter); ok {
+			if err := validator.Validate(instance); err != nil {
+				validation.Add(err, ErrorCodeInvalidFilter, "filter")
+			}
 		}
 	}
 
 	return nil
 }
 
+func sortedKeys(filters map[string]interface{}) []string {
+	keys := make([]string, 0, len(filters))
+	for key := range filters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 func (g *GormAdapter) applyFilters(instance OptionsInterface) error {
 	if len(g.filtersWhitelist) == 0 {
 		for key, _ := range instance.GetFilters() {
@@ -97,23 +133,37 @@ func (g *GormAdapter) applyFilters(instance OptionsInterface) error {
 	return nil
 }
 
+//applyQuery searches the q parameter in the AllowedSearch columns, without them the string filters are searched
 func (g *GormAdapter) applyQuery(instance OptionsInterface) error {
+	if len(g.searchColumns) > 0 && instance.GetQuery() != nil {
+		return g.applySearch(*instance.GetQuery())
+	}
+
 	if len(g.filtersWhitelist) == 0 || instance.GetQuery() == nil {
 		return nil
 	}
 
-	var q *gorm.DB
+	var conditions []clause.Expression
 	for _, whiteListFilterEntry := range g.filtersWhitelist {
 		if _k, ok := whiteListFilterEntry.(string); ok {
-			if q == nil {
-				q = g.db.Or(fmt.Sprintf("%s LIKE ?",_k),fmt.Sprintf("%%%s%%",*instance.GetQuery()))
-			}else {
-				q = q.Or(fmt.Sprintf("%s LIKE ?",_k),fmt.Sprintf("%%%s%%",*instance.GetQuery()))
-			}
+			conditions = append(conditions, clause.Like{
+				Column: clause.Column{Name: _k},
+				Value:  fmt.Sprintf("%%%s%%", *instance.GetQuery()),
+			})
 		}
 	}
 
-	g.db.Where(q)
+	if len(conditions) > 0 {
+		g.db.Where(anyOf(conditions))
+	}
 
 	return nil
 }
+
+//anyOf joins the conditions with OR, a single OR condition would otherwise be OR-ed onto the previous where clause by gorm
+func anyOf(conditions []clause.Expression) clause.Expression {
+	if len(conditions) == 1 {
+		return conditions[0]
+	}
+	return clause.Or(conditions...)
+}
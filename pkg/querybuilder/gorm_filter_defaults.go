@@ -0,0 +1,141 @@
+package querybuilder
+
+import (
+	"fmt"
+)
+
+//GormDefaultedFilter is implemented by filters with a default value applied when the filter is not supplied,
+//and values such as all or an empty string that skip the filter when they are supplied
+type GormDefaultedFilter interface {
+	GormAllowedFilter
+	DefaultValue() (interface{}, bool)
+	IgnoredValues() []string
+}
+
+//filterDefaults is embedded by the filters to implement GormDefaultedFilter, the filters expose Default and Ignore
+//setters returning themselves so they can be chained
+type filterDefaults struct {
+	defaultValue interface{}
+	hasDefault   bool
+	ignored      []string
+}
+
+//DefaultValue returns the value applied when the filter is not supplied and whether one was set
+func (f *filterDefaults) DefaultValue() (interface{}, bool) {
+	return f.defaultValue, f.hasDefault
+}
+
+//IgnoredValues returns the values that skip the filter
+func (f *filterDefaults) IgnoredValues() []string {
+	return f.ignored
+}
+
+func (f *filterDefaults) setDefault(value interface{}) {
+	f.defaultValue = value
+	f.hasDefault = true
+}
+
+func (f *filterDefaults) addIgnored(values ...string) {
+	f.ignored = append(f.ignored, values...)
+}
+
+//applyFilterDefaults adds the default value of the whitelisted filters that were not supplied and removes the
+//ignored values of the ones that were, a filter left without values is skipped
+func (g *GormAdapter) applyFilterDefaults(instance OptionsInterface) OptionsInterface {
+	var rawFilters map[string]interface{}
+	rawGetter, hasRaw := instance.(interface{ GetRawFilters() map[string]interface{} })
+	if hasRaw {
+		rawFilters = rawGetter.GetRawFilters()
+	}
+
+	filters := make(map[string]interface{}, len(instance.GetFilters()))
+	for key, value := range instance.GetFilters() {
+		filters[key] = value
+	}
+	raw := make(map[string]interface{}, len(rawFilters))
+	for key, value := range rawFilters {
+		raw[key] = value
+	}
+
+	changed := false
+	for _, entry := range g.filtersWhitelist {
+		filter, ok := entry.(GormDefaultedFilter)
+		if !ok {
+			continue
+		}
+
+		for _, key := range filter.Keys() {
+			value, supplied := filters[key]
+			if !supplied {
+				if defaultValue, hasDefault := filter.DefaultValue(); hasDefault {
+					filters[key] = defaultValue
+					raw[key] = defaultValue
+					changed = true
+				}
+				continue
+			}
+
+			if len(filter.IgnoredValues()) == 0 {
+				continue
+			}
+			kept, keep := withoutIgnoredValues(value, filter.IgnoredValues())
+			if !keep {
+				delete(filters, key)
+				delete(raw, key)
+				changed = true
+				continue
+			}
+			filters[key] = kept
+			if rawValue, hasRawValue := raw[key]; hasRawValue {
+				raw[key], _ = withoutIgnoredValues(rawValue, filter.IgnoredValues())
+			}
+			changed = true
+		}
+	}
+
+	if !changed {
+		return instance
+	}
+	if !hasRaw {
+		raw = nil
+	}
+	return filterOptions{OptionsInterface: instance, filters: filters, rawFilters: raw}
+}
+
+//withoutIgnoredValues removes the ignored values from a single value or a list, it returns false when no value is left.
+//Values are compared as text so that filter[status]=all matches Ignore("all") whatever type was guessed for it
+func withoutIgnoredValues(value interface{}, ignored []string) (interface{}, bool) {
+	isIgnored := func(item interface{}) bool {
+		for _, ignoredValue := range ignored {
+			if fmt.Sprint(item) == ignoredValue {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return v, true
+	case []interface{}:
+		var kept []interface{}
+		for _, item := range v {
+			if !isIgnored(item) {
+				kept = append(kept, item)
+			}
+		}
+		switch len(kept) {
+		case 0:
+			return nil, false
+		case 1:
+			return kept[0], true
+		default:
+			return kept, true
+		}
+	default:
+		if value == nil || isIgnored(value) {
+			return nil, false
+		}
+		return value, true
+	}
+}
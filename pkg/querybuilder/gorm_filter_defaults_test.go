@@ -0,0 +1,133 @@
+package querybuilder_test
+
+import (
+	"testing"
+
+	"github.com/akacokafor/gorm-query-builder/pkg/querybuilder"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGormAdapter_FilterDefaults(t *testing.T) {
+	db := openPaginationDB(t, 6)
+
+	tests := []struct {
+		name     string
+		idFilter *querybuilder.GormAllowedFilterOperator
+		url      string
+		expected []uint
+	}{
+		{
+			name:     "Should apply the default value when the filter is not supplied",
+			url:      "https://example.com",
+			expected: []uint{1, 3, 5},
+		},
+		{
+			name:     "Should apply the supplied value instead of the default",
+			url:      "https://example.com?filter[status]=closed",
+			expected: []uint{2, 4, 6},
+		},
+		{
+			name:     "Should skip the filter when an ignored value is supplied",
+			url:      "https://example.com?filter[status]=all",
+			expected: []uint{1, 2, 3, 4, 5, 6},
+		},
+		{
+			name:     "Should skip the filter when an empty value is ignored",
+			url:      "https://example.com?filter[status]=",
+			expected: []uint{1, 2, 3, 4, 5, 6},
+		},
+		{
+			name:     "Should remove the ignored values from a list",
+			url:      "https://example.com?filter[status]=all,closed",
+			expected: []uint{2, 4, 6},
+		},
+		{
+			name:     "Should skip the search filter when an empty value is ignored",
+			url:      "https://example.com?filter[status]=all&filter[name]=",
+			expected: []uint{1, 2, 3, 4, 5, 6},
+		},
+		{
+			name:     "Should skip operator filters with an ignored value",
+			url:      "https://example.com?filter[status]=all&filter[id]=any",
+			expected: []uint{1, 2, 3, 4, 5, 6},
+		},
+		{
+			name:     "Should coerce the default value of an operator filter",
+			idFilter: querybuilder.NewGormAllowedFilterOperator("id").Default(map[string]interface{}{"gte": "4"}),
+			url:      "https://example.com?filter[status]=all&filter[name]=user",
+			expected: []uint{4, 5, 6},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idFilter := tt.idFilter
+			if idFilter == nil {
+				idFilter = querybuilder.NewGormAllowedFilterOperator("id").Ignore("any")
+			}
+
+			got, err := querybuilder.NewGormAdapter(db.Model(&paginatedUser{})).
+				AllowedFilters([]interface{}{
+					querybuilder.NewGormAllowedFilterExact("status").Default("open").Ignore("all", ""),
+					querybuilder.NewGormAllowedFilterSearch("name").Ignore(""),
+					idFilter,
+				}).
+				AllowedSorts([]interface{}{"id"}).
+				DefaultSort(querybuilder.Sort{Name: "id", Ascending: true}).
+				ExecuteOnUrl(tt.url)
+			assert.Nil(t, err)
+
+			var users []paginatedUser
+			assert.Nil(t, got.Find(&users).Error)
+
+			var ids []uint
+			for _, user := range users {
+				ids = append(ids, user.ID)
+			}
+			assert.Equal(t, tt.expected, ids)
+		})
+	}
+}
+
+func TestGormAllowedFilterTrashed_Defaults(t *testing.T) {
+	db := openSeededDB(t, []interface{}{&softDeletedPost{}}, &[]softDeletedPost{{Title: "live"}, {Title: "trashed"}})
+	db.Delete(&softDeletedPost{}, "title = ?", "trashed")
+
+	tests := []struct {
+		name     string
+		url      string
+		expected []string
+	}{
+		{
+			name:     "Should apply the default value when the filter is not supplied",
+			url:      "https://example.com",
+			expected: []string{"live", "trashed"},
+		},
+		{
+			name:     "Should apply the supplied value instead of the default",
+			url:      "https://example.com?filter[trashed]=only",
+			expected: []string{"trashed"},
+		},
+		{
+			name:     "Should skip the filter when an ignored value is supplied",
+			url:      "https://example.com?filter[trashed]=all",
+			expected: []string{"live"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := querybuilder.NewGormAdapter(db.Model(&softDeletedPost{})).
+				AllowedFilters([]interface{}{querybuilder.NewGormAllowedFilterTrashed().Default(querybuilder.TrashedWith).Ignore("all")}).
+				ExecuteOnUrl(tt.url)
+			assert.Nil(t, err)
+
+			var posts []softDeletedPost
+			assert.Nil(t, got.Order("id").Find(&posts).Error)
+
+			var titles []string
+			for _, post := range posts {
+				titles = append(titles, post.Title)
+			}
+			assert.Equal(t, tt.expected, titles)
+		})
+	}
+}
@@ -3,11 +3,15 @@ package querybuilder
 import (
 	"fmt"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"sort"
+	"strings"
 )
 
 type GormAllowedFilterExact struct {
-	propName string
-
+	filterDefaults
+	propName   string
+	columnName string
 }
 
 func (g *GormAllowedFilterExact) Keys() []string {
@@ -19,18 +23,61 @@ func (g *GormAllowedFilterExact) Execute(db *gorm.DB, options OptionsInterface)
 	if val == nil {
 		return nil
 	}
-	db.Where(g.propName,val)
+	condition, err := relationCondition(db, g.columnName, func(column clause.Column) (clause.Expression, error) {
+		if values, ok := val.([]interface{}); ok {
+			return clause.IN{Column: column, Values: values}, nil
+		}
+		return clause.Eq{Column: column, Value: val}, nil
+	})
+	if err != nil {
+		return err
+	}
+	db.Where(condition)
 	return nil
 }
 
+func (g *GormAllowedFilterExact) Column() string {
+	return g.columnName
+}
+
+func (g *GormAllowedFilterExact) CoerceValue(value interface{}, coerce GormFilterValueCoercer) (interface{}, error) {
+	if _, isMap := value.(map[string]interface{}); isMap {
+		//operators are reported by Validate
+		return value, nil
+	}
+	return coerceValues(value, coerce)
+}
+
+func (g *GormAllowedFilterExact) Validate(options OptionsInterface) error {
+	return rejectOperators(g.propName, options)
+}
+
+//Default sets the value applied when the filter is not supplied, e.g. "active"
+func (g *GormAllowedFilterExact) Default(value interface{}) *GormAllowedFilterExact {
+	g.setDefault(value)
+	return g
+}
+
+//Ignore sets the values that skip the filter when supplied, e.g. "all" and ""
+func (g *GormAllowedFilterExact) Ignore(values ...string) *GormAllowedFilterExact {
+	g.addIgnored(values...)
+	return g
+}
+
 func NewGormAllowedFilterExact(propName string) *GormAllowedFilterExact {
-	return &GormAllowedFilterExact{propName: propName}
+	return NewGormAllowedFilterExactAlias(propName, propName)
 }
 
+//NewGormAllowedFilterExactAlias exposes the column as filter[alias] without revealing the column name
+func NewGormAllowedFilterExactAlias(alias string, columnName string) *GormAllowedFilterExact {
+	return &GormAllowedFilterExact{propName: alias, columnName: columnName}
+}
 
-type GormAllowedFilterSearch struct {
-	propName string
 
+type GormAllowedFilterSearch struct {
+	filterDefaults
+	propName   string
+	columnName string
 }
 
 func (g *GormAllowedFilterSearch) Keys() []string {
@@ -42,11 +89,488 @@ func (g *GormAllowedFilterSearch) Execute(db *gorm.DB, options OptionsInterface)
 	if val == nil {
 		return nil
 	}
-	sqlQuery := fmt.Sprintf("`%s` LIKE ?",g.propName)
-	db.Where(sqlQuery,fmt.Sprintf("%%%s%%",val))
+	values, ok := val.([]interface{})
+	if !ok {
+		values = []interface{}{val}
+	}
+
+	condition, err := relationCondition(db, g.columnName, func(column clause.Column) (clause.Expression, error) {
+		var conditions []clause.Expression
+		for _, value := range values {
+			conditions = append(conditions, clause.Like{Column: column, Value: fmt.Sprintf("%%%v%%", value)})
+		}
+		return anyOf(conditions), nil
+	})
+	if err != nil {
+		return err
+	}
+	db.Where(condition)
 	return nil
 }
 
+func (g *GormAllowedFilterSearch) Validate(options OptionsInterface) error {
+	return rejectOperators(g.propName, options)
+}
+
+//rejectOperators reports the nested keys of filters that do not support operators, e.g. filter[name][gte]=1 on a
+//partial filter, which would otherwise be matched as text
+func rejectOperators(propName string, options OptionsInterface) error {
+	operators, ok := options.GetFilters()[propName].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	validation := &QueryValidationError{}
+	for _, operator := range sortedKeys(operators) {
+		validation.Add(&QueryParameterError{
+			Code:      ErrorCodeInvalidFilterOperator,
+			Parameter: fmt.Sprintf("filter[%s]", propName),
+			Value:     operator,
+			Detail:    fmt.Sprintf("filter %s does not support operators", propName),
+			Err:       ErrInvalidFilterOperator,
+		}, ErrorCodeInvalidFilterOperator, propName)
+	}
+	return validation
+}
+
+func (g *GormAllowedFilterSearch) Column() string {
+	return g.columnName
+}
+
+//CoerceValue keeps the values as supplied, a partial match compares text so filter[zip]=01234 must not become 1234
+func (g *GormAllowedFilterSearch) CoerceValue(value interface{}, coerce GormFilterValueCoercer) (interface{}, error) {
+	return value, nil
+}
+
+//Default sets the value applied when the filter is not supplied, e.g. "john"
+func (g *GormAllowedFilterSearch) Default(value interface{}) *GormAllowedFilterSearch {
+	g.setDefault(value)
+	return g
+}
+
+//Ignore sets the values that skip the filter when supplied, e.g. ""
+func (g *GormAllowedFilterSearch) Ignore(values ...string) *GormAllowedFilterSearch {
+	g.addIgnored(values...)
+	return g
+}
+
 func NewGormAllowedFilterSearch(propName string) *GormAllowedFilterSearch {
-	return &GormAllowedFilterSearch{propName: propName}
+	return NewGormAllowedFilterSearchAlias(propName, propName)
+}
+
+//NewGormAllowedFilterSearchAlias exposes the column as filter[alias] without revealing the column name
+func NewGormAllowedFilterSearchAlias(alias string, columnName string) *GormAllowedFilterSearch {
+	return &GormAllowedFilterSearch{propName: alias, columnName: columnName}
+}
+
+type GormFilterOperator string
+
+const (
+	FilterOperatorEq      GormFilterOperator = "eq"
+	FilterOperatorNe      GormFilterOperator = "ne"
+	FilterOperatorGt      GormFilterOperator = "gt"
+	FilterOperatorGte     GormFilterOperator = "gte"
+	FilterOperatorLt      GormFilterOperator = "lt"
+	FilterOperatorLte     GormFilterOperator = "lte"
+	FilterOperatorBetween GormFilterOperator = "between"
+)
+
+var filterOperators = []GormFilterOperator{
+	FilterOperatorEq,
+	FilterOperatorNe,
+	FilterOperatorGt,
+	FilterOperatorGte,
+	FilterOperatorLt,
+	FilterOperatorLte,
+	FilterOperatorBetween,
+}
+
+//GormAllowedFilterOperator compares the column with an operator supplied as filter[price][gte]=100 or filter[price]=gte:100,
+//a value without an operator is compared for equality
+type GormAllowedFilterOperator struct {
+	filterDefaults
+	propName   string
+	columnName string
+	operators  []GormFilterOperator
+}
+
+func (g *GormAllowedFilterOperator) Keys() []string {
+	return []string{g.propName}
+}
+
+func (g *GormAllowedFilterOperator) Execute(db *gorm.DB, options OptionsInterface) error {
+	val := options.GetFilters()[g.propName]
+	if val == nil {
+		return nil
+	}
+
+	conditions := g.parseConditions(val, simpleParseString)
+	for _, operator := range sortedOperators(conditions) {
+		if !g.isAllowedOperator(operator) {
+			return fmt.Errorf("invalid filter operator %s for %s, %w", operator, g.propName, ErrInvalidFilterOperator)
+		}
+	}
+
+	//all comparisons of a relationship filter must hold for the same related row, so they share one subquery
+	condition, err := relationCondition(db, g.columnName, func(column clause.Column) (clause.Expression, error) {
+		var expressions []clause.Expression
+		for _, operator := range sortedOperators(conditions) {
+			expression, err := g.buildCondition(column, operator, conditions[operator])
+			if err != nil {
+				return nil, err
+			}
+			expressions = append(expressions, expression)
+		}
+		if len(expressions) == 1 {
+			return expressions[0], nil
+		}
+		return clause.And(expressions...), nil
+	})
+	if err != nil {
+		return err
+	}
+	db.Where(condition)
+	return nil
+}
+
+func (g *GormAllowedFilterOperator) Validate(options OptionsInterface) error {
+	val := options.GetFilters()[g.propName]
+	if val == nil {
+		return nil
+	}
+
+	validation := &QueryValidationError{}
+	for _, operator := range sortedOperators(g.parseConditions(val, simpleParseString)) {
+		if !g.isAllowedOperator(operator) {
+			validation.Add(&QueryParameterError{
+				Code:      ErrorCodeInvalidFilterOperator,
+				Parameter: fmt.Sprintf("filter[%s]", g.propName),
+				Value:     string(operator),
+				Allowed:   g.allowedOperatorNames(),
+				Detail:    fmt.Sprintf("invalid filter operator %s for %s", operator, g.propName),
+				Err:       ErrInvalidFilterOperator,
+			}, ErrorCodeInvalidFilterOperator, g.propName)
+		}
+	}
+
+	if validation.HasErrors() {
+		return validation
+	}
+	return nil
+}
+
+func (g *GormAllowedFilterOperator) Column() string {
+	return g.columnName
+}
+
+//CoerceValue coerces the operands of the known operators, the value is returned as a map of operator to operand
+func (g *GormAllowedFilterOperator) CoerceValue(value interface{}, coerce GormFilterValueCoercer) (interface{}, error) {
+	conditions := make(map[string]interface{})
+	for operator, operand := range g.parseConditions(value, func(item string) interface{} {
+		return item
+	}) {
+		if !isFilterOperator(operator) {
+			conditions[string(operator)] = operand
+			continue
+		}
+		if bounds, isString := operand.(string); isString && operator == FilterOperatorBetween {
+			var values []interface{}
+			for _, bound := range strings.Split(bounds, ",") {
+				values = append(values, bound)
+			}
+			operand = values
+		}
+
+		coerced, err := coerceValues(operand, coerce)
+		if err != nil {
+			return nil, err
+		}
+		conditions[string(operator)] = coerced
+	}
+	return conditions, nil
+}
+
+func (g *GormAllowedFilterOperator) allowedOperatorNames() []string {
+	operators := g.operators
+	if len(operators) == 0 {
+		operators = filterOperators
+	}
+
+	names := make([]string, 0, len(operators))
+	for _, operator := range operators {
+		names = append(names, string(operator))
+	}
+	return names
+}
+
+//parseConditions normalizes the supported value formats into a map of operator to value, parse converts the operand of
+//values such as gte:100
+func (g *GormAllowedFilterOperator) parseConditions(val interface{}, parse func(item string) interface{}) map[GormFilterOperator]interface{} {
+	conditions := make(map[GormFilterOperator]interface{})
+	switch v := val.(type) {
+	case map[string]interface{}:
+		for operator, operand := range v {
+			conditions[GormFilterOperator(strings.ToLower(operator))] = operand
+		}
+	case string:
+		if operator, operand, ok := splitOperatorPrefix(v); ok {
+			conditions[operator] = parse(operand)
+		} else {
+			conditions[FilterOperatorEq] = v
+		}
+	case []interface{}:
+		//filter[created_at]=between:2024-01-01,2024-02-01 is split into ["between:2024-01-01", "2024-02-01"]
+		if len(v) == 0 {
+			conditions[FilterOperatorEq] = v
+			break
+		}
+		first, isString := v[0].(string)
+		if !isString {
+			conditions[FilterOperatorEq] = v
+			break
+		}
+		operator, operand, ok := splitOperatorPrefix(first)
+		if !ok {
+			conditions[FilterOperatorEq] = v
+			break
+		}
+		operands := append([]interface{}{parse(operand)}, v[1:]...)
+		conditions[operator] = operands
+	default:
+		conditions[FilterOperatorEq] = v
+	}
+	return conditions
+}
+
+func (g *GormAllowedFilterOperator) isAllowedOperator(operator GormFilterOperator) bool {
+	if !isFilterOperator(operator) {
+		return false
+	}
+	if len(g.operators) == 0 {
+		return true
+	}
+	for _, allowed := range g.operators {
+		if allowed == operator {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *GormAllowedFilterOperator) buildCondition(column clause.Column, operator GormFilterOperator, operand interface{}) (clause.Expression, error) {
+	values, isList := operand.([]interface{})
+	switch operator {
+	case FilterOperatorNe:
+		if isList {
+			return clause.Not(clause.IN{Column: column, Values: values}), nil
+		}
+		return clause.Neq{Column: column, Value: operand}, nil
+	case FilterOperatorGt:
+		return clause.Gt{Column: column, Value: operand}, nil
+	case FilterOperatorGte:
+		return clause.Gte{Column: column, Value: operand}, nil
+	case FilterOperatorLt:
+		return clause.Lt{Column: column, Value: operand}, nil
+	case FilterOperatorLte:
+		return clause.Lte{Column: column, Value: operand}, nil
+	case FilterOperatorBetween:
+		if !isList {
+			for _, bound := range strings.Split(fmt.Sprint(operand), ",") {
+				values = append(values, simpleParseString(bound))
+			}
+		}
+		if len(values) != 2 {
+			return nil, fmt.Errorf("filter %s between requires two values, %w", g.propName, ErrInvalidFilterOperator)
+		}
+		return clause.Expr{
+			SQL:  "? BETWEEN ? AND ?",
+			Vars: []interface{}{column, values[0], values[1]},
+		}, nil
+	default:
+		if isList {
+			return clause.IN{Column: column, Values: values}, nil
+		}
+		return clause.Eq{Column: column, Value: operand}, nil
+	}
+}
+
+//splitOperatorPrefix splits values such as gte:100 into the operator and the operand
+func splitOperatorPrefix(value string) (GormFilterOperator, string, bool) {
+	index := strings.Index(value, ":")
+	if index <= 0 {
+		return "", "", false
+	}
+
+	operator := GormFilterOperator(strings.ToLower(value[:index]))
+	if !isFilterOperator(operator) {
+		return "", "", false
+	}
+	return operator, value[index+1:], true
+}
+
+func isFilterOperator(operator GormFilterOperator) bool {
+	for _, known := range filterOperators {
+		if known == operator {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedOperators(conditions map[GormFilterOperator]interface{}) []GormFilterOperator {
+	operators := make([]GormFilterOperator, 0, len(conditions))
+	for operator := range conditions {
+		operators = append(operators, operator)
+	}
+	sort.Slice(operators, func(i, j int) bool {
+		return operators[i] < operators[j]
+	})
+	return operators
+}
+
+//Default sets the value applied when the filter is not supplied, e.g. map[string]interface{}{"gte": 100}
+func (g *GormAllowedFilterOperator) Default(value interface{}) *GormAllowedFilterOperator {
+	g.setDefault(value)
+	return g
+}
+
+//Ignore sets the values that skip the filter when supplied, e.g. "any"
+func (g *GormAllowedFilterOperator) Ignore(values ...string) *GormAllowedFilterOperator {
+	g.addIgnored(values...)
+	return g
+}
+
+//NewGormAllowedFilterOperator creates an operator filter, when no operators are supplied every operator is allowed
+func NewGormAllowedFilterOperator(propName string, operators ...GormFilterOperator) *GormAllowedFilterOperator {
+	return NewGormAllowedFilterOperatorAlias(propName, propName, operators...)
+}
+
+//NewGormAllowedFilterOperatorAlias exposes the column as filter[alias] without revealing the column name
+func NewGormAllowedFilterOperatorAlias(alias string, columnName string, operators ...GormFilterOperator) *GormAllowedFilterOperator {
+	return &GormAllowedFilterOperator{propName: alias, columnName: columnName, operators: operators}
+}
+
+type (
+	GormFilterScope        func(db *gorm.DB, value interface{}) *gorm.DB
+	GormAllowedFilterScope struct {
+		filterDefaults
+		propName string
+		scope    GormFilterScope
+	}
+)
+
+func (g *GormAllowedFilterScope) Keys() []string {
+	return []string{g.propName}
+}
+
+//Execute registers the scope on the query, the scope receives the parsed filter value which is a slice for multiple values
+func (g *GormAllowedFilterScope) Execute(db *gorm.DB, options OptionsInterface) error {
+	val := options.GetFilters()[g.propName]
+	if val == nil {
+		return nil
+	}
+	db.Scopes(func(tx *gorm.DB) *gorm.DB {
+		return g.scope(tx, val)
+	})
+	return nil
+}
+
+//Default sets the value applied when the filter is not supplied, e.g. true
+func (g *GormAllowedFilterScope) Default(value interface{}) *GormAllowedFilterScope {
+	g.setDefault(value)
+	return g
+}
+
+//Ignore sets the values that skip the filter when supplied, e.g. "all"
+func (g *GormAllowedFilterScope) Ignore(values ...string) *GormAllowedFilterScope {
+	g.addIgnored(values...)
+	return g
+}
+
+func NewGormAllowedFilterScope(propName string, scope GormFilterScope) *GormAllowedFilterScope {
+	return &GormAllowedFilterScope{
+		propName: propName,
+		scope:    scope,
+	}
+}
+
+const (
+	TrashedWith    = "with"
+	TrashedOnly    = "only"
+	TrashedWithout = "without"
+)
+
+//GormAllowedFilterTrashed toggles the gorm soft delete scope with filter[trashed]=with|only|without
+type GormAllowedFilterTrashed struct {
+	filterDefaults
+	propName   string
+	columnName string
+}
+
+func (g *GormAllowedFilterTrashed) Keys() []string {
+	return []string{g.propName}
+}
+
+func (g *GormAllowedFilterTrashed) Execute(db *gorm.DB, options OptionsInterface) error {
+	val := options.GetFilters()[g.propName]
+	if val == nil {
+		return nil
+	}
+
+	if err := g.Validate(options); err != nil {
+		return err
+	}
+
+	switch strings.ToLower(fmt.Sprint(val)) {
+	case TrashedWith:
+		db.Unscoped()
+	case TrashedOnly:
+		db.Unscoped().Where(clause.Expr{
+			SQL:  "? IS NOT NULL",
+			Vars: []interface{}{clause.Column{Table: clause.CurrentTable, Name: g.columnName}},
+		})
+	}
+	return nil
+}
+
+func (g *GormAllowedFilterTrashed) Validate(options OptionsInterface) error {
+	val := options.GetFilters()[g.propName]
+	if val == nil {
+		return nil
+	}
+
+	switch strings.ToLower(fmt.Sprint(val)) {
+	case TrashedWith, TrashedOnly, TrashedWithout:
+		return nil
+	}
+	return &QueryParameterError{
+		Code:      ErrorCodeInvalidFilterValue,
+		Parameter: fmt.Sprintf("filter[%s]", g.propName),
+		Value:     fmt.Sprint(val),
+		Allowed:   []string{TrashedWith, TrashedOnly, TrashedWithout},
+		Detail:    fmt.Sprintf("invalid %s filter value %v", g.propName, val),
+		Err:       ErrInvalidFilterQuery,
+	}
+}
+
+//Default sets the value applied when the filter is not supplied, e.g. TrashedWith
+func (g *GormAllowedFilterTrashed) Default(value interface{}) *GormAllowedFilterTrashed {
+	g.setDefault(value)
+	return g
+}
+
+//Ignore sets the values that skip the filter when supplied, e.g. "all"
+func (g *GormAllowedFilterTrashed) Ignore(values ...string) *GormAllowedFilterTrashed {
+	g.addIgnored(values...)
+	return g
+}
+
+//ColumnName sets the soft delete column, defaults to deleted_at
+func (g *GormAllowedFilterTrashed) ColumnName(columnName string) *GormAllowedFilterTrashed {
+	g.columnName = columnName
+	return g
+}
+
+func NewGormAllowedFilterTrashed() *GormAllowedFilterTrashed {
+	return &GormAllowedFilterTrashed{propName: "trashed", columnName: "deleted_at"}
 }
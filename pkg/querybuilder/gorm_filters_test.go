@@ -0,0 +1,88 @@
+package querybuilder_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/akacokafor/gorm-query-builder/pkg/querybuilder"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type softDeletedPost struct {
+	ID        uint
+	Title     string
+	DeletedAt gorm.DeletedAt
+}
+
+func TestGormAllowedFilterTrashed(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		url       string
+		validator func(t *testing.T, sqlString string, err error)
+	}{
+		{
+			name: "Should exclude trashed records by default",
+			url:  "https://example.com",
+			validator: func(t *testing.T, sqlString string, err error) {
+				assert.Nil(t, err)
+				assert.Contains(t, sqlString, "`soft_deleted_posts`.`deleted_at` IS NULL")
+			},
+		},
+		{
+			name: "Should exclude trashed records when trashed is without",
+			url:  "https://example.com?filter[trashed]=without",
+			validator: func(t *testing.T, sqlString string, err error) {
+				assert.Nil(t, err)
+				assert.Contains(t, sqlString, "`soft_deleted_posts`.`deleted_at` IS NULL")
+			},
+		},
+		{
+			name: "Should include trashed records when trashed is with",
+			url:  "https://example.com?filter[trashed]=with",
+			validator: func(t *testing.T, sqlString string, err error) {
+				assert.Nil(t, err)
+				assert.NotContains(t, sqlString, "deleted_at")
+			},
+		},
+		{
+			name: "Should only return trashed records when trashed is only",
+			url:  "https://example.com?filter[trashed]=only",
+			validator: func(t *testing.T, sqlString string, err error) {
+				assert.Nil(t, err)
+				assert.Contains(t, sqlString, "`soft_deleted_posts`.`deleted_at` IS NOT NULL")
+				assert.NotContains(t, sqlString, "IS NULL")
+			},
+		},
+		{
+			name: "Should throw error for unknown trashed values",
+			url:  "https://example.com?filter[trashed]=maybe",
+			validator: func(t *testing.T, sqlString string, err error) {
+				assert.True(t, errors.Is(err, querybuilder.ErrInvalidFilterQuery))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := querybuilder.NewGormAdapter(db.Model(&softDeletedPost{})).
+				AllowedFilters([]interface{}{
+					"title",
+					querybuilder.NewGormAllowedFilterTrashed(),
+				}).
+				ExecuteOnUrl(tt.url)
+
+			var sqlString string
+			if err == nil {
+				stmt := got.Find(&[]softDeletedPost{}).Statement
+				sqlString = stmt.SQL.String()
+			}
+			tt.validator(t, sqlString, err)
+		})
+	}
+}
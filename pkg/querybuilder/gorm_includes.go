@@ -0,0 +1,161 @@
+package querybuilder
+
+import (
+	"gorm.io/gorm"
+)
+
+//GormAllowedInclude is an include that can be requested with include=name
+type GormAllowedInclude interface {
+	Name() string
+	Execute(db *gorm.DB, options OptionsInterface) error
+}
+
+//GormPreloadInclude is implemented by includes that preload a relationship, it is reported by GetRelationships
+type GormPreloadInclude interface {
+	GormAllowedInclude
+	Preload() string
+}
+
+//GormAllowedIncludeAlias exposes a gorm association as include=alias, the relationship is the association
+//name as passed to Preload, e.g. "Author" or "Posts.Comments"
+type GormAllowedIncludeAlias struct {
+	name         string
+	relationship string
+}
+
+func (g *GormAllowedIncludeAlias) Name() string {
+	return g.name
+}
+
+func (g *GormAllowedIncludeAlias) Relationship() string {
+	return g.relationship
+}
+
+func (g *GormAllowedIncludeAlias) Preload() string {
+	return g.relationship
+}
+
+func (g *GormAllowedIncludeAlias) Execute(db *gorm.DB, options OptionsInterface) error {
+	preloadRelationship(db, g.name, g.relationship, options, nil)
+	return nil
+}
+
+func NewGormAllowedIncludeAlias(alias string, relationship string) *GormAllowedIncludeAlias {
+	return &GormAllowedIncludeAlias{
+		name:         alias,
+		relationship: relationship,
+	}
+}
+
+type (
+	//GormIncludeCallback adds conditions to the query preloading the relationship, e.g. only published comments
+	GormIncludeCallback        func(db *gorm.DB) *gorm.DB
+	GormAllowedIncludeCallback struct {
+		name         string
+		relationship string
+		callback     GormIncludeCallback
+	}
+)
+
+func (g *GormAllowedIncludeCallback) Name() string {
+	return g.name
+}
+
+func (g *GormAllowedIncludeCallback) Preload() string {
+	return g.relationship
+}
+
+func (g *GormAllowedIncludeCallback) Execute(db *gorm.DB, options OptionsInterface) error {
+	preloadRelationship(db, g.name, g.relationship, options, g.callback)
+	return nil
+}
+
+//NewGormAllowedIncludeCallback preloads include=name with the conditions added by the callback, e.g.
+//
+//	NewGormAllowedIncludeCallback("comments", func(db *gorm.DB) *gorm.DB {
+//		return db.Where("published = ?", true).Order("created_at DESC")
+//	})
+//
+//gorm preloads the relationship of every parent with a single query, so a Limit in the callback limits the related
+//rows of all parents together, not the rows of each parent
+func NewGormAllowedIncludeCallback(name string, callback GormIncludeCallback) *GormAllowedIncludeCallback {
+	return NewGormAllowedIncludeCallbackAlias(name, normalizeIncludeName(name), callback)
+}
+
+//NewGormAllowedIncludeCallbackAlias preloads the relationship as include=alias with the conditions added by the callback
+func NewGormAllowedIncludeCallbackAlias(alias string, relationship string, callback GormIncludeCallback) *GormAllowedIncludeCallback {
+	return &GormAllowedIncludeCallback{
+		name:         alias,
+		relationship: relationship,
+		callback:     callback,
+	}
+}
+
+//GormAllowedIncludeAggregate selects the number of related rows, include=commentsCount, or whether any exist,
+//include=commentsExists, as a column of the result, e.g. comments_count. The column is scanned into a read only
+//field of the model, such as CommentsCount int64 `gorm:"->;-:migration"`, or into a map
+type GormAllowedIncludeAggregate struct {
+	name         string
+	relationship string
+	columnName   string
+	exists       bool
+}
+
+func (g *GormAllowedIncludeAggregate) Name() string {
+	return g.name
+}
+
+func (g *GormAllowedIncludeAggregate) Relationship() string {
+	return g.relationship
+}
+
+func (g *GormAllowedIncludeAggregate) Execute(db *gorm.DB, options OptionsInterface) error {
+	return selectAggregate(db, g)
+}
+
+//ColumnName sets the column the aggregate is selected as
+func (g *GormAllowedIncludeAggregate) ColumnName(columnName string) *GormAllowedIncludeAggregate {
+	g.columnName = columnName
+	return g
+}
+
+//column returns the column the aggregate is selected as, by default the column the naming strategy of the db gives the
+//read only field <relationship>Count or <relationship>Exists of the model
+func (g *GormAllowedIncludeAggregate) column(db *gorm.DB) string {
+	if g.columnName != "" {
+		return g.columnName
+	}
+
+	field := g.relationship + "Count"
+	if g.exists {
+		field = g.relationship + "Exists"
+	}
+	return db.NamingStrategy.ColumnName("", field)
+}
+
+//NewGormAllowedIncludeCount exposes the number of related rows as include=<relationship>Count selected as the column of <relationship>Count
+func NewGormAllowedIncludeCount(relationship string) *GormAllowedIncludeAggregate {
+	return NewGormAllowedIncludeCountAlias(relationship+"Count", relationship)
+}
+
+//NewGormAllowedIncludeCountAlias exposes the number of related rows as include=alias
+func NewGormAllowedIncludeCountAlias(alias string, relationship string) *GormAllowedIncludeAggregate {
+	return &GormAllowedIncludeAggregate{
+		name:         alias,
+		relationship: relationship,
+	}
+}
+
+//NewGormAllowedIncludeExists exposes whether related rows exist as include=<relationship>Exists selected as the column of <relationship>Exists
+func NewGormAllowedIncludeExists(relationship string) *GormAllowedIncludeAggregate {
+	return NewGormAllowedIncludeExistsAlias(relationship+"Exists", relationship)
+}
+
+//NewGormAllowedIncludeExistsAlias exposes whether related rows exist as include=alias
+func NewGormAllowedIncludeExistsAlias(alias string, relationship string) *GormAllowedIncludeAggregate {
+	return &GormAllowedIncludeAggregate{
+		name:         alias,
+		relationship: relationship,
+		exists:       true,
+	}
+}
@@ -1,14 +1,74 @@
 package querybuilder
 
-import "log"
+import (
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"strings"
+)
+
+func (g *GormAdapter) validateIncludes(instance OptionsInterface, validation *QueryValidationError) error {
+	for _, entry := range g.includesWhitelist {
+		_, isString := entry.(string)
+		_, isAllowedInclude := entry.(GormAllowedInclude)
+		if !isString && !isAllowedInclude {
+			return errors.New("all includes must be string or objects that implement GormAllowedInclude")
+		}
+	}
+
+	allowed := g.getIncludeNames()
+	for _, include := range instance.GetIncludes() {
+		parameterError := &QueryParameterError{
+			Code:      ErrorCodeInvalidInclude,
+			Parameter: "include",
+			Value:     include,
+			Allowed:   allowed,
+			Err:       ErrInvalidIncludeQuery,
+		}
+
+		switch {
+		case g.maxIncludeDepth > 0 && len(strings.Split(include, ".")) > g.maxIncludeDepth:
+			parameterError.Detail = fmt.Sprintf("include %s exceeds the maximum depth of %d", include, g.maxIncludeDepth)
+		case len(g.includesWhitelist) == 0 && !g.unlistedIncludes:
+			parameterError.Detail = fmt.Sprintf("invalid include %s, includes are not allowed", include)
+		case len(g.includesWhitelist) > 0 && !g.isValidInclude(include):
+			parameterError.Detail = fmt.Sprintf("invalid include %s", include)
+		default:
+			continue
+		}
+		validation.Add(parameterError, ErrorCodeInvalidInclude, "include")
+	}
+	return nil
+}
+
+func (g *GormAdapter) getIncludeNames() []string {
+	var names []string
+	for _, entry := range g.includesWhitelist {
+		if name, ok := entry.(string); ok {
+			names = append(names, name)
+		}
+		if include, ok := entry.(GormAllowedInclude); ok {
+			names = append(names, include.Name())
+		}
+	}
+	return names
+}
+
+func (g *GormAdapter) isValidInclude(include string) bool {
+	for _, name := range g.getIncludeNames() {
+		if name == include {
+			return true
+		}
+	}
+	return false
+}
 
 func (g *GormAdapter) applyIncludes(instance OptionsInterface) error {
 	if len(g.includesWhitelist) == 0 {
-		log.Print(instance.GetIncludes())
 		for _, val := range instance.GetIncludes() {
 			relationshipName := g.normalizeIncludeName(val)
-			g.addRelationship(relationshipName)
-			g.db.Preload(relationshipName)
+			g.preload(val, relationshipName, instance)
 		}
 		return nil
 	}
@@ -18,23 +78,83 @@ func (g *GormAdapter) applyIncludes(instance OptionsInterface) error {
 			if _k, ok := whiteListIncludeEntry.(string); ok {
 				if _k == suppliedInclude {
 					relationshipName := g.normalizeIncludeName(_k)
-					g.addRelationship(relationshipName)
-					g.db.Preload(relationshipName)
+					g.preload(_k, relationshipName, instance)
 				}
 			}
 
-			//if op, ok := whiteListFilterEntry.(GormAllowedFilter); ok {
-			//	for _, _k := range op.Keys() {
-			//		if _k == suppliedFilterKey {
-			//			if err :=  op.Execute(g.db,instance); err != nil {
-			//				return err
-			//			}
-			//		}
-			//	}
-			//}
+			if include, ok := whiteListIncludeEntry.(GormAllowedInclude); ok {
+				if include.Name() == suppliedInclude {
+					if err := include.Execute(g.db, instance); err != nil {
+						return err
+					}
+					if preload, ok := include.(GormPreloadInclude); ok {
+						g.addRelationship(preload.Preload())
+					}
+				}
+			}
 		}
 	}
 
 	return nil
 }
 
+//selectAggregate adds the correlated count or exists subquery of the include to the selected columns
+func selectAggregate(db *gorm.DB, include *GormAllowedIncludeAggregate) error {
+	rootSchema := getSchema(db)
+	if rootSchema == nil {
+		return fmt.Errorf("include %s requires a model", include.Name())
+	}
+	relationship := findRelationship(rootSchema, include.Relationship())
+	if relationship == nil {
+		return fmt.Errorf("include %s refers to the unknown relationship %s", include.Name(), include.Relationship())
+	}
+
+	columnName := include.column(db)
+	var expression clause.Expression = newRelationSubquery(relationship, clause.CurrentTable, columnName, "COUNT(*)")
+	if include.exists {
+		expression = existsExpression{subquery: newRelationSubquery(relationship, clause.CurrentTable, columnName, "1")}
+	}
+
+	selection := aggregateSelect{}
+	if existing, ok := db.Statement.Clauses["SELECT"]; ok {
+		switch current := existing.Expression.(type) {
+		case aggregateSelect:
+			selection = current
+		case clause.Select:
+			selection.columns = current.Columns
+		}
+	}
+	selection.aggregates = append(selection.aggregates, aggregateColumn{expression: expression, alias: columnName})
+	db.Clauses(clause.Select{Expression: selection})
+	return nil
+}
+
+//aggregateSelect selects the requested columns, or all columns of the table, followed by the aggregates of the includes
+type aggregateSelect struct {
+	columns    []clause.Column
+	aggregates []aggregateColumn
+}
+
+func (s aggregateSelect) Build(builder clause.Builder) {
+	if len(s.columns) == 0 {
+		builder.WriteQuoted(clause.Table{Name: clause.CurrentTable})
+		builder.WriteString(".*")
+	}
+	for index, column := range s.columns {
+		if index > 0 {
+			builder.WriteByte(',')
+		}
+		builder.WriteQuoted(column)
+	}
+	for _, aggregate := range s.aggregates {
+		builder.WriteByte(',')
+		aggregate.expression.Build(builder)
+		builder.WriteString(" AS ")
+		builder.WriteQuoted(aggregate.alias)
+	}
+}
+
+type aggregateColumn struct {
+	expression clause.Expression
+	alias      string
+}
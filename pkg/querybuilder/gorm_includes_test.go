@@ -0,0 +1,357 @@
+package querybuilder_test
+
+import (
+	"testing"
+
+	"github.com/akacokafor/gorm-query-builder/pkg/querybuilder"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+type countedPost struct {
+	ID            uint
+	Title         string
+	Comments      []relationComment `gorm:"foreignKey:PostID"`
+	Tags          []relationTag     `gorm:"many2many:relation_post_tags;joinForeignKey:RelationPostID"`
+	CommentsCount int64             `gorm:"->;-:migration"`
+	TagsExists    bool              `gorm:"->;-:migration"`
+	Replies       int64             `gorm:"->;-:migration"`
+}
+
+func (countedPost) TableName() string {
+	return "relation_posts"
+}
+
+func TestGormAdapter_AggregateIncludes(t *testing.T) {
+	db := openRelationDB(t)
+
+	tests := []struct {
+		name     string
+		url      string
+		validate func(t *testing.T, posts []countedPost)
+	}{
+		{
+			name: "Should select the number of related rows",
+			url:  "https://example.com?include=commentsCount",
+			validate: func(t *testing.T, posts []countedPost) {
+				assert.Equal(t, []int64{2, 1, 0}, []int64{posts[0].CommentsCount, posts[1].CommentsCount, posts[2].CommentsCount})
+				assert.Equal(t, "first", posts[0].Title)
+			},
+		},
+		{
+			name: "Should select whether related rows exist through a pivot table",
+			url:  "https://example.com?include=tagsExists",
+			validate: func(t *testing.T, posts []countedPost) {
+				assert.Equal(t, []bool{true, true, false}, []bool{posts[0].TagsExists, posts[1].TagsExists, posts[2].TagsExists})
+			},
+		},
+		{
+			name: "Should select several aggregates along with the requested fields",
+			url:  "https://example.com?include=commentsCount,tagsExists,replies&fields[relation_posts]=id",
+			validate: func(t *testing.T, posts []countedPost) {
+				assert.Empty(t, posts[0].Title)
+				assert.Equal(t, int64(2), posts[0].CommentsCount)
+				assert.Equal(t, int64(2), posts[0].Replies)
+				assert.True(t, posts[1].TagsExists)
+			},
+		},
+		{
+			name: "Should not select aggregates that were not requested",
+			url:  "https://example.com",
+			validate: func(t *testing.T, posts []countedPost) {
+				assert.Equal(t, int64(0), posts[0].CommentsCount)
+				assert.False(t, posts[0].TagsExists)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := querybuilder.NewGormAdapter(db.Model(&countedPost{})).
+				AllowedIncludes([]interface{}{
+					querybuilder.NewGormAllowedIncludeCount("comments"),
+					querybuilder.NewGormAllowedIncludeExists("tags"),
+					querybuilder.NewGormAllowedIncludeCountAlias("replies", "Comments").ColumnName("replies"),
+				}).
+				AllowedFields([]interface{}{"id", "title"}).
+				AllowedSorts([]interface{}{"id"}).
+				DefaultSort(querybuilder.Sort{Name: "id", Ascending: true}).
+				ExecuteOnUrl(tt.url)
+			assert.Nil(t, err)
+
+			var posts []countedPost
+			assert.Nil(t, got.Find(&posts).Error)
+			assert.Len(t, posts, 3)
+			tt.validate(t, posts)
+		})
+	}
+}
+
+func TestGormAdapter_AggregateIncludes_Map(t *testing.T) {
+	db := openRelationDB(t)
+
+	var rows []map[string]interface{}
+	result, err := querybuilder.NewGormAdapter(db.Model(&countedPost{})).
+		AllowedIncludes([]interface{}{querybuilder.NewGormAllowedIncludeCount("comments")}).
+		AllowedSorts([]interface{}{"id"}).
+		DefaultSort(querybuilder.Sort{Name: "id", Ascending: true}).
+		PaginateIntoOnUrl("https://example.com?include=commentsCount&size=2", &rows)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), result.Total)
+	assert.Len(t, rows, 2)
+	assert.EqualValues(t, 2, rows[0]["comments_count"])
+	assert.Equal(t, "first", rows[0]["title"])
+}
+
+type renamingNamer struct {
+	schema.NamingStrategy
+	columns map[string]string
+}
+
+func (n renamingNamer) ColumnName(table, column string) string {
+	if name, ok := n.columns[column]; ok {
+		return name
+	}
+	return n.NamingStrategy.ColumnName(table, column)
+}
+
+func TestGormAdapter_AggregateIncludes_NamingStrategy(t *testing.T) {
+	openRelationDB(t)
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{
+		NamingStrategy: renamingNamer{columns: map[string]string{"CommentsCount": "total_comments", "TagsExists": "has_tags"}},
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	got, err := querybuilder.NewGormAdapter(db.Model(&countedPost{})).
+		AllowedIncludes([]interface{}{
+			querybuilder.NewGormAllowedIncludeCount("Comments"),
+			querybuilder.NewGormAllowedIncludeExists("Tags"),
+		}).
+		AllowedSorts([]interface{}{"id"}).
+		DefaultSort(querybuilder.Sort{Name: "id", Ascending: true}).
+		ExecuteOnUrl("https://example.com?include=CommentsCount,TagsExists")
+	assert.Nil(t, err)
+
+	var posts []countedPost
+	assert.Nil(t, got.Find(&posts).Error)
+	if assert.Len(t, posts, 3) {
+		assert.Equal(t, []int64{2, 1, 0}, []int64{posts[0].CommentsCount, posts[1].CommentsCount, posts[2].CommentsCount})
+		assert.Equal(t, []bool{true, true, false}, []bool{posts[0].TagsExists, posts[1].TagsExists, posts[2].TagsExists})
+	}
+}
+
+func TestGormAdapter_CallbackIncludes(t *testing.T) {
+	db := openRelationDB(t)
+
+	tests := []struct {
+		name          string
+		url           string
+		relationships []string
+		validate      func(t *testing.T, posts []relationPost)
+	}{
+		{
+			name:          "Should preload with the conditions of the callback",
+			url:           "https://example.com?include=comments",
+			relationships: []string{"Comments"},
+			validate: func(t *testing.T, posts []relationPost) {
+				assert.Len(t, posts[0].Comments, 1)
+				assert.Equal(t, "meh", posts[0].Comments[0].Body)
+				assert.Len(t, posts[1].Comments, 1)
+			},
+		},
+		{
+			name:          "Should preload a differently named association",
+			url:           "https://example.com?include=latestReplies",
+			relationships: []string{"Comments"},
+			validate: func(t *testing.T, posts []relationPost) {
+				assert.Len(t, posts[0].Comments, 2)
+				assert.Equal(t, "meh", posts[0].Comments[0].Body)
+				assert.Equal(t, "great post", posts[0].Comments[1].Body)
+			},
+		},
+		{
+			name:          "Should combine the callback with the requested fields",
+			url:           "https://example.com?include=latestReplies&fields[latestReplies]=id,post_id",
+			relationships: []string{"Comments"},
+			validate: func(t *testing.T, posts []relationPost) {
+				assert.Len(t, posts[0].Comments, 2)
+				assert.Empty(t, posts[0].Comments[0].Body)
+				assert.Equal(t, uint(2), posts[0].Comments[0].ID)
+			},
+		},
+		{
+			name:          "Should limit the preloaded rows of all parents together",
+			url:           "https://example.com?include=firstReply",
+			relationships: []string{"Comments"},
+			validate: func(t *testing.T, posts []relationPost) {
+				assert.Len(t, posts[0].Comments, 1)
+				assert.Empty(t, posts[1].Comments)
+				assert.Empty(t, posts[2].Comments)
+			},
+		},
+		{
+			name:          "Should preload nested relationships with an alias",
+			url:           "https://example.com?include=writer",
+			relationships: []string{"Author.Country"},
+			validate: func(t *testing.T, posts []relationPost) {
+				assert.Equal(t, "NG", posts[0].Author.Country.Code)
+				assert.Empty(t, posts[0].Comments)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adapter := querybuilder.NewGormAdapter(db.Model(&relationPost{})).
+				AllowedIncludes([]interface{}{
+					querybuilder.NewGormAllowedIncludeCallback("comments", func(db *gorm.DB) *gorm.DB {
+						return db.Where("body = ?", "meh")
+					}),
+					querybuilder.NewGormAllowedIncludeCallbackAlias("latestReplies", "Comments", func(db *gorm.DB) *gorm.DB {
+						return db.Order("id DESC")
+					}),
+					querybuilder.NewGormAllowedIncludeCallbackAlias("firstReply", "Comments", func(db *gorm.DB) *gorm.DB {
+						return db.Order("id").Limit(1)
+					}),
+					querybuilder.NewGormAllowedIncludeAlias("writer", "Author.Country"),
+				}).
+				AllowedFields([]interface{}{"latestReplies.id", "latestReplies.post_id"}).
+				AllowedSorts([]interface{}{"id"}).
+				DefaultSort(querybuilder.Sort{Name: "id", Ascending: true})
+
+			got, err := adapter.ExecuteOnUrl(tt.url)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.relationships, adapter.GetRelationships())
+
+			var posts []relationPost
+			assert.Nil(t, got.Find(&posts).Error)
+			assert.Len(t, posts, 3)
+			tt.validate(t, posts)
+		})
+	}
+}
+
+func TestGormAdapter_ValidateIncludes(t *testing.T) {
+	db := openRelationDB(t)
+
+	tests := []struct {
+		name      string
+		whitelist []interface{}
+		unlisted  bool
+		maxDepth  int
+		url       string
+		wantErr   bool
+	}{
+		{
+			name:    "Should reject includes when none are white listed",
+			url:     "https://example.com?include=comments",
+			wantErr: true,
+		},
+		{
+			name:     "Should preload any include when unlisted includes are allowed",
+			unlisted: true,
+			url:      "https://example.com?include=comments",
+		},
+		{
+			name:      "Should reject includes that are not white listed",
+			whitelist: []interface{}{"author", querybuilder.NewGormAllowedIncludeCount("comments")},
+			url:       "https://example.com?include=author,tags",
+			wantErr:   true,
+		},
+		{
+			name:      "Should accept white listed includes",
+			whitelist: []interface{}{"author", querybuilder.NewGormAllowedIncludeCount("comments")},
+			url:       "https://example.com?include=author,commentsCount",
+		},
+		{
+			name:      "Should reject includes nested deeper than the maximum depth",
+			whitelist: []interface{}{"author", "author.country"},
+			maxDepth:  1,
+			url:       "https://example.com?include=author.country",
+			wantErr:   true,
+		},
+		{
+			name:     "Should apply the maximum depth to unlisted includes",
+			unlisted: true,
+			maxDepth: 2,
+			url:      "https://example.com?include=author.country",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := querybuilder.NewGormAdapter(db.Model(&relationPost{})).
+				AllowedIncludes(tt.whitelist).
+				AllowUnlistedIncludes(tt.unlisted).
+				MaxIncludeDepth(tt.maxDepth).
+				ExecuteOnUrl(tt.url)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, querybuilder.ErrInvalidIncludeQuery)
+				var validation *querybuilder.QueryValidationError
+				if assert.ErrorAs(t, err, &validation) {
+					assert.Equal(t, querybuilder.ErrorCodeInvalidInclude, validation.Errors[0].Code)
+				}
+				return
+			}
+			assert.Nil(t, err)
+		})
+	}
+}
+
+func TestGormAdapter_SparseFieldsWithIncludes(t *testing.T) {
+	db := openRelationDB(t)
+
+	tests := []struct {
+		name      string
+		url       string
+		validator func(t *testing.T, posts []relationPost)
+	}{
+		{
+			name: "Should attach included rows when their keys were not requested",
+			url:  "https://example.com?include=author,comments&fields[author]=name&fields[comments]=body",
+			validator: func(t *testing.T, posts []relationPost) {
+				assert.Equal(t, "Ada", posts[0].Author.Name)
+				assert.Equal(t, posts[0].AuthorID, posts[0].Author.ID)
+				if assert.Len(t, posts[0].Comments, 2) {
+					assert.Equal(t, "great post", posts[0].Comments[0].Body)
+				}
+			},
+		},
+		{
+			name: "Should attach included rows when the keys of the root table were not requested",
+			url:  "https://example.com?include=author,comments&fields[relation_posts]=title",
+			validator: func(t *testing.T, posts []relationPost) {
+				assert.Equal(t, "first", posts[0].Title)
+				assert.Equal(t, "Ada", posts[0].Author.Name)
+				assert.Len(t, posts[0].Comments, 2)
+				assert.Len(t, posts[1].Comments, 1)
+			},
+		},
+		{
+			name: "Should attach nested includes through a restricted relationship",
+			url:  "https://example.com?include=author.country&fields[author]=name&fields[author.country]=code",
+			validator: func(t *testing.T, posts []relationPost) {
+				assert.Equal(t, "Ada", posts[0].Author.Name)
+				assert.Equal(t, "NG", posts[0].Author.Country.Code)
+				assert.Equal(t, "FI", posts[1].Author.Country.Code)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := querybuilder.NewGormAdapter(db.Model(&relationPost{})).
+				AllowUnlistedIncludes(true).
+				AllowedSorts([]interface{}{"id"}).
+				DefaultSort(querybuilder.Sort{Name: "id", Ascending: true}).
+				ExecuteOnUrl(tt.url)
+			assert.Nil(t, err)
+
+			var posts []relationPost
+			assert.Nil(t, got.Find(&posts).Error)
+			if assert.Len(t, posts, 3) {
+				tt.validator(t, posts)
+			}
+		})
+	}
+}
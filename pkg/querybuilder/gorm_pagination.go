@@ -0,0 +1,195 @@
+package querybuilder
+
+import (
+	"fmt"
+	"gorm.io/gorm"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+const (
+	DefaultPage     = 1
+	DefaultPageSize = 30
+)
+
+//PageSizeMode decides what happens to a requested page size that is not allowed
+type PageSizeMode int
+
+const (
+	//PageSizeClamp replaces the size with the closest allowed size
+	PageSizeClamp PageSizeMode = iota
+	//PageSizeReject fails the query with ErrInvalidPageSize
+	PageSizeReject
+)
+
+type PaginationMeta struct {
+	CurrentPage int   `json:"current_page"`
+	PerPage     int   `json:"per_page"`
+	Total       int64 `json:"total"`
+	LastPage    int   `json:"last_page"`
+	From        int   `json:"from"`
+	To          int   `json:"to"`
+}
+
+//PaginationResult holds a page of items along with the metadata needed to render the paginator
+type PaginationResult struct {
+	Items          interface{} `json:"data"`
+	PaginationMeta `json:"meta"`
+	//Links is only set when the query was applied with an url, e.g. with PaginateIntoOnUrl
+	Links *PaginationLinks `json:"links,omitempty"`
+}
+
+//PaginateInto paginates the query, loads the current page into dest and counts the total number of matching rows
+func (g *GormAdapter) PaginateInto(optionsInstance OptionsInterface, dest interface{}) (*PaginationResult, error) {
+	db, err := g.Paginate(optionsInstance)
+	if err != nil {
+		return nil, err
+	}
+
+	var total int64
+	if err := g.countQuery(db).Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	if err := db.Find(dest).Error; err != nil {
+		return nil, err
+	}
+
+	page, size, _ := g.getPagination(optionsInstance)
+	result := newPaginationResult(dest, total, page, size)
+	if result.Links, err = g.offsetLinks(optionsInstance, page, size, result.LastPage); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+//PaginateIntoOnUrl parses the url and paginates the query into dest, the result links to the neighbouring pages
+func (g *GormAdapter) PaginateIntoOnUrl(url string, dest interface{}) (*PaginationResult, error) {
+	optionsInstance, err := g.parseUrl(url)
+	if err != nil {
+		return nil, err
+	}
+	g.requestUrl = url
+
+	return g.PaginateInto(optionsInstance, dest)
+}
+
+//countQuery clones the query without the clauses that do not affect the number of matching rows
+func (g *GormAdapter) countQuery(db *gorm.DB) *gorm.DB {
+	countDB := db.Session(&gorm.Session{}).Clauses()
+	delete(countDB.Statement.Clauses, "ORDER BY")
+	delete(countDB.Statement.Clauses, "LIMIT")
+	delete(countDB.Statement.Clauses, "SELECT")
+	countDB.Statement.Selects = nil
+	countDB.Statement.Preloads = nil
+	return countDB
+}
+
+func newPaginationResult(dest interface{}, total int64, page int, size int) *PaginationResult {
+	result := &PaginationResult{
+		Items: dest,
+		PaginationMeta: PaginationMeta{
+			CurrentPage: page,
+			PerPage:     size,
+			Total:       total,
+			LastPage:    1,
+		},
+	}
+
+	if size > 0 && total > 0 {
+		result.LastPage = int((total + int64(size) - 1) / int64(size))
+	}
+
+	if count := countItems(dest); count > 0 {
+		result.From = (page-1)*size + 1
+		result.To = result.From + count - 1
+	}
+
+	return result
+}
+
+func countItems(dest interface{}) int {
+	value := reflect.Indirect(reflect.ValueOf(dest))
+	if value.Kind() == reflect.Slice || value.Kind() == reflect.Array {
+		return value.Len()
+	}
+	return 0
+}
+
+func (c queryConfig) getDefaultPage() int {
+	if c.defaultPage > 0 {
+		return c.defaultPage
+	}
+	return DefaultPage
+}
+
+func (c queryConfig) getDefaultPageSize() int {
+	if c.defaultPageSize > 0 {
+		return c.defaultPageSize
+	}
+	return DefaultPageSize
+}
+
+//getPageSize returns the size to paginate with clamped to the allowed sizes, valid is false when the requested size is not allowed
+func (c queryConfig) getPageSize(instance OptionsInterface) (size int, valid bool) {
+	if instance.GetSize() == nil {
+		return c.getDefaultPageSize(), true
+	}
+
+	size = *instance.GetSize()
+	switch {
+	case size <= 0:
+		return c.getDefaultPageSize(), false
+	case len(c.allowedPageSizes) > 0:
+		return closestPageSize(c.allowedPageSizes, size)
+	case c.maxPageSize > 0 && size > c.maxPageSize:
+		return c.maxPageSize, false
+	}
+	return size, true
+}
+
+//closestPageSize returns the largest allowed size that does not exceed the requested size, or the smallest allowed size
+func closestPageSize(allowedSizes []int, size int) (int, bool) {
+	sizes := append([]int(nil), allowedSizes...)
+	sort.Ints(sizes)
+
+	closest := sizes[0]
+	for _, allowed := range sizes {
+		if allowed == size {
+			return size, true
+		}
+		if allowed < size {
+			closest = allowed
+		}
+	}
+	return closest, false
+}
+
+func (g *GormAdapter) validatePageSize(instance OptionsInterface, validation *QueryValidationError) {
+	if g.pageSizeMode != PageSizeReject || instance.GetSize() == nil {
+		return
+	}
+	if _, valid := g.getPageSize(instance); valid {
+		return
+	}
+
+	size := *instance.GetSize()
+	parameterError := &QueryParameterError{
+		Code:      ErrorCodeInvalidSize,
+		Parameter: "size",
+		Value:     strconv.Itoa(size),
+		Detail:    "size must be greater than 0",
+		Err:       ErrInvalidPageSize,
+	}
+	switch {
+	case len(g.allowedPageSizes) > 0:
+		for _, allowed := range g.allowedPageSizes {
+			parameterError.Allowed = append(parameterError.Allowed, strconv.Itoa(allowed))
+		}
+		parameterError.Detail = fmt.Sprintf("size %d is not allowed", size)
+	case g.maxPageSize > 0 && size > 0:
+		parameterError.Detail = fmt.Sprintf("size must not be greater than %d", g.maxPageSize)
+	}
+	validation.Add(parameterError, ErrorCodeInvalidSize, "size")
+}
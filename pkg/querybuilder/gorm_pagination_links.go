@@ -0,0 +1,135 @@
+package querybuilder
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+//PaginationLinks holds the urls of the neighbouring pages, links that do not exist are left empty,
+//cursor pagination has no last link
+type PaginationLinks struct {
+	First string `json:"first,omitempty"`
+	Prev  string `json:"prev,omitempty"`
+	Next  string `json:"next,omitempty"`
+	Last  string `json:"last,omitempty"`
+}
+
+//BaseUrl sets the public url the pagination links start with, e.g. https://api.example.com/v1 for a service behind a
+//proxy, the path of the request is appended to it. By default the links use the url passed to the *OnUrl methods
+func (g *GormAdapter) BaseUrl(baseUrl string) *GormAdapter {
+	g.baseUrl = baseUrl
+	return g
+}
+
+//offsetLinks builds the links of the pages around page, they are only built for queries applied with an url
+func (g *GormAdapter) offsetLinks(instance OptionsInterface, page int, size int, lastPage int) (*PaginationLinks, error) {
+	options, ok := instance.(*Options)
+	if !ok || g.requestUrl == "" {
+		return nil, nil
+	}
+	options = effectiveSize(options, size)
+
+	link, err := g.linkBuilder()
+	if err != nil {
+		return nil, err
+	}
+
+	links := &PaginationLinks{
+		First: link(options.WithPage(1)),
+		Last:  link(options.WithPage(lastPage)),
+	}
+	if page > 1 {
+		previous := page - 1
+		if previous > lastPage {
+			previous = lastPage
+		}
+		links.Prev = link(options.WithPage(previous))
+	}
+	if page < lastPage {
+		links.Next = link(options.WithPage(page + 1))
+	}
+	return links, nil
+}
+
+//cursorLinks builds the links of the pages around the current cursor, they are only built for queries applied with an url
+func (g *GormAdapter) cursorLinks(instance OptionsInterface, size int, nextCursor string, prevCursor string) (*PaginationLinks, error) {
+	options, ok := instance.(*Options)
+	if !ok || g.requestUrl == "" {
+		return nil, nil
+	}
+	options = effectiveSize(options, size)
+
+	link, err := g.linkBuilder()
+	if err != nil {
+		return nil, err
+	}
+
+	links := &PaginationLinks{First: link(options.WithCursor(""))}
+	if prevCursor != "" {
+		links.Prev = link(options.WithCursor(prevCursor))
+	}
+	if nextCursor != "" {
+		links.Next = link(options.WithCursor(nextCursor))
+	}
+	return links, nil
+}
+
+//effectiveSize replaces the size requested by the client with the size the page was loaded with, e.g. after it was
+//clamped to MaxPageSize, so that the links do not repeat a size the adapter did not use
+func effectiveSize(options *Options, size int) *Options {
+	if options.Size == nil || *options.Size == size {
+		return options
+	}
+	return options.WithSize(size)
+}
+
+//linkBuilder returns a func building the url of the options, query parameters of the request that are not read by
+//ParseUrl, e.g. api_version, are kept in the links
+func (g *GormAdapter) linkBuilder() (func(options *Options) string, error) {
+	requestUrl, err := url.Parse(g.requestUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	base := &url.URL{Path: requestUrl.Path}
+	if g.baseUrl != "" {
+		if base, err = url.Parse(g.baseUrl); err != nil {
+			return nil, fmt.Errorf("invalid base url %s, %v", g.baseUrl, err)
+		}
+		base.Path = strings.TrimSuffix(base.Path, "/") + requestUrl.Path
+	} else {
+		base.Scheme = requestUrl.Scheme
+		base.Host = requestUrl.Host
+		base.User = requestUrl.User
+	}
+
+	foreign := url.Values{}
+	for key, values := range requestUrl.Query() {
+		if !isOptionsParameter(key) {
+			foreign[key] = values
+		}
+	}
+
+	return func(options *Options) string {
+		query := options.Encode()
+		if encoded := foreign.Encode(); encoded != "" && query != "" {
+			query = encoded + "&" + query
+		} else if encoded != "" {
+			query = encoded
+		}
+
+		link := *base
+		link.RawQuery = query
+		return link.String()
+	}, nil
+}
+
+//isOptionsParameter reports whether ParseUrl reads the query parameter
+func isOptionsParameter(key string) bool {
+	switch key {
+	case "q", "page", "size", "cursor", "sort", "include":
+		return true
+	}
+	return strings.HasPrefix(key, "filter[") || strings.HasPrefix(key, "fields[")
+}
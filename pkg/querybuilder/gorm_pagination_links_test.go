@@ -0,0 +1,127 @@
+package querybuilder_test
+
+import (
+	"testing"
+
+	"github.com/akacokafor/gorm-query-builder/pkg/querybuilder"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGormAdapter_PaginationLinks(t *testing.T) {
+	db := openPaginationDB(t, 45)
+
+	tests := []struct {
+		name        string
+		baseUrl     string
+		maxPageSize int
+		url         string
+		expected    *querybuilder.PaginationLinks
+	}{
+		{
+			name: "Should link to the neighbouring pages keeping the filters and sorts",
+			url:  "https://example.com/users?filter[status]=open&sort=-name&page=2&size=5",
+			expected: &querybuilder.PaginationLinks{
+				First: "https://example.com/users?filter[status]=open&page=1&size=5&sort=-name",
+				Prev:  "https://example.com/users?filter[status]=open&page=1&size=5&sort=-name",
+				Next:  "https://example.com/users?filter[status]=open&page=3&size=5&sort=-name",
+				Last:  "https://example.com/users?filter[status]=open&page=5&size=5&sort=-name",
+			},
+		},
+		{
+			name: "Should not link before the first or after the last page",
+			url:  "https://example.com/users?size=50",
+			expected: &querybuilder.PaginationLinks{
+				First: "https://example.com/users?page=1&size=50",
+				Last:  "https://example.com/users?page=1&size=50",
+			},
+		},
+		{
+			name: "Should keep the parameters that are not query options",
+			url:  "/users?page=9&api_version=2&size=10",
+			expected: &querybuilder.PaginationLinks{
+				First: "/users?api_version=2&page=1&size=10",
+				Prev:  "/users?api_version=2&page=5&size=10",
+				Last:  "/users?api_version=2&page=5&size=10",
+			},
+		},
+		{
+			name:    "Should start the links with the base url",
+			baseUrl: "https://api.example.com/v1/",
+			url:     "/users?page=5&size=10",
+			expected: &querybuilder.PaginationLinks{
+				First: "https://api.example.com/v1/users?page=1&size=10",
+				Prev:  "https://api.example.com/v1/users?page=4&size=10",
+				Last:  "https://api.example.com/v1/users?page=5&size=10",
+			},
+		},
+		{
+			name:        "Should link with the size the page was loaded with",
+			maxPageSize: 20,
+			url:         "https://example.com/users?page=2&size=100",
+			expected: &querybuilder.PaginationLinks{
+				First: "https://example.com/users?page=1&size=20",
+				Prev:  "https://example.com/users?page=1&size=20",
+				Next:  "https://example.com/users?page=3&size=20",
+				Last:  "https://example.com/users?page=3&size=20",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var users []paginatedUser
+			result, err := querybuilder.NewGormAdapter(db.Model(&paginatedUser{})).
+				AllowedFilters([]interface{}{querybuilder.NewGormAllowedFilterExact("status")}).
+				AllowedSorts([]interface{}{"name"}).
+				BaseUrl(tt.baseUrl).
+				MaxPageSize(tt.maxPageSize).
+				PaginateIntoOnUrl(tt.url, &users)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expected, result.Links)
+		})
+	}
+
+	t.Run("Should not link pages of options that were not parsed from an url", func(t *testing.T) {
+		var users []paginatedUser
+		result, err := querybuilder.NewGormAdapter(db.Model(&paginatedUser{})).
+			PaginateInto(mustParseUrl(t, "https://example.com/users?page=2"), &users)
+		assert.Nil(t, err)
+		assert.Nil(t, result.Links)
+	})
+}
+
+func TestGormAdapter_CursorPaginationLinks(t *testing.T) {
+	db := openPaginationDB(t, 25)
+	newAdapter := func() *querybuilder.GormAdapter {
+		return querybuilder.NewGormAdapter(db.Model(&paginatedUser{})).
+			AllowedSorts([]interface{}{"id"})
+	}
+
+	var first []paginatedUser
+	result, err := newAdapter().CursorPaginateIntoOnUrl("https://example.com/users?sort=id&size=10", &first)
+	assert.Nil(t, err)
+	assert.Equal(t, &querybuilder.PaginationLinks{
+		First: "https://example.com/users?size=10&sort=id",
+		Next:  "https://example.com/users?cursor=" + result.NextCursor + "&size=10&sort=id",
+	}, result.Links)
+
+	var second []paginatedUser
+	result, err = newAdapter().CursorPaginateIntoOnUrl(result.Links.Next, &second)
+	assert.Nil(t, err)
+	assert.Equal(t, uint(11), second[0].ID)
+	assert.Equal(t, "https://example.com/users?size=10&sort=id", result.Links.First)
+	assert.Equal(t, "https://example.com/users?cursor="+result.PrevCursor+"&size=10&sort=id", result.Links.Prev)
+	assert.Equal(t, "https://example.com/users?cursor="+result.NextCursor+"&size=10&sort=id", result.Links.Next)
+	assert.Empty(t, result.Links.Last)
+
+	var previous []paginatedUser
+	result, err = newAdapter().CursorPaginateIntoOnUrl(result.Links.Prev, &previous)
+	assert.Nil(t, err)
+	assert.Equal(t, first, previous)
+
+	var clamped []paginatedUser
+	result, err = newAdapter().MaxPageSize(5).CursorPaginateIntoOnUrl("https://example.com/users?sort=id&size=100", &clamped)
+	assert.Nil(t, err)
+	assert.Len(t, clamped, 5)
+	assert.Equal(t, "https://example.com/users?size=5&sort=id", result.Links.First)
+	assert.Equal(t, "https://example.com/users?cursor="+result.NextCursor+"&size=5&sort=id", result.Links.Next)
+}
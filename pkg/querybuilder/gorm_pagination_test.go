@@ -0,0 +1,200 @@
+package querybuilder_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/akacokafor/gorm-query-builder/pkg/querybuilder"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type paginatedUser struct {
+	ID     uint
+	Name   string
+	Status string
+}
+
+func openPaginationDB(t *testing.T, count int) *gorm.DB {
+	var users []interface{}
+	for i := 1; i <= count; i++ {
+		status := "open"
+		if i%2 == 0 {
+			status = "closed"
+		}
+		users = append(users, &paginatedUser{Name: fmt.Sprintf("user %02d", i), Status: status})
+	}
+	return openSeededDB(t, []interface{}{&paginatedUser{}}, users...)
+}
+
+func TestGormAdapter_PaginateInto(t *testing.T) {
+	tests := []struct {
+		name      string
+		url       string
+		validator func(t *testing.T, result *querybuilder.PaginationResult, users []paginatedUser, err error)
+	}{
+		{
+			name: "Should paginate with the default page and size",
+			url:  "https://example.com",
+			validator: func(t *testing.T, result *querybuilder.PaginationResult, users []paginatedUser, err error) {
+				assert.Nil(t, err)
+				assert.Len(t, users, 30)
+				assert.Equal(t, int64(45), result.Total)
+				assert.Equal(t, 1, result.CurrentPage)
+				assert.Equal(t, 30, result.PerPage)
+				assert.Equal(t, 2, result.LastPage)
+				assert.Equal(t, 1, result.From)
+				assert.Equal(t, 30, result.To)
+			},
+		},
+		{
+			name: "Should count all filtered rows and return the requested page",
+			url:  "https://example.com?filter[status]=open&sort=-id&page=3&size=5",
+			validator: func(t *testing.T, result *querybuilder.PaginationResult, users []paginatedUser, err error) {
+				assert.Nil(t, err)
+				assert.Len(t, users, 5)
+				assert.Equal(t, int64(23), result.Total)
+				assert.Equal(t, 3, result.CurrentPage)
+				assert.Equal(t, 5, result.LastPage)
+				assert.Equal(t, 11, result.From)
+				assert.Equal(t, 15, result.To)
+				assert.Equal(t, uint(25), users[0].ID)
+			},
+		},
+		{
+			name: "Should return empty bounds past the last page",
+			url:  "https://example.com?page=10&size=10&fields[paginated_users]=id,name",
+			validator: func(t *testing.T, result *querybuilder.PaginationResult, users []paginatedUser, err error) {
+				assert.Nil(t, err)
+				assert.Len(t, users, 0)
+				assert.Equal(t, int64(45), result.Total)
+				assert.Equal(t, 5, result.LastPage)
+				assert.Equal(t, 0, result.From)
+				assert.Equal(t, 0, result.To)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openPaginationDB(t, 45)
+			var users []paginatedUser
+			result, err := querybuilder.NewGormAdapter(db.Model(&paginatedUser{})).
+				AllowedFilters([]interface{}{querybuilder.NewGormAllowedFilterExact("status")}).
+				AllowedSorts([]interface{}{"id"}).
+				PaginateIntoOnUrl(tt.url, &users)
+			tt.validator(t, result, users, err)
+		})
+	}
+}
+
+func TestGormAdapter_PageSizeLimits(t *testing.T) {
+	db := openPaginationDB(t, 45)
+
+	tests := []struct {
+		name      string
+		url       string
+		configure func(adapter *querybuilder.GormAdapter)
+		wantErr   error
+		wantPage  int
+		wantSize  int
+	}{
+		{
+			name: "Should use the configured default page and size",
+			url:  "https://example.com",
+			configure: func(adapter *querybuilder.GormAdapter) {
+				adapter.DefaultPage(2).DefaultPageSize(10)
+			},
+			wantPage: 2,
+			wantSize: 10,
+		},
+		{
+			name: "Should clamp a size above the maximum",
+			url:  "https://example.com?size=1000000",
+			configure: func(adapter *querybuilder.GormAdapter) {
+				adapter.MaxPageSize(20)
+			},
+			wantPage: 1,
+			wantSize: 20,
+		},
+		{
+			name: "Should replace a negative size with the default size",
+			url:  "https://example.com?size=-5",
+			configure: func(adapter *querybuilder.GormAdapter) {
+				adapter.DefaultPageSize(15)
+			},
+			wantPage: 1,
+			wantSize: 15,
+		},
+		{
+			name: "Should clamp a size to the closest smaller allowed size",
+			url:  "https://example.com?size=30",
+			configure: func(adapter *querybuilder.GormAdapter) {
+				adapter.AllowedPageSizes(10, 25, 50)
+			},
+			wantPage: 1,
+			wantSize: 25,
+		},
+		{
+			name: "Should clamp a size below the allowed sizes to the smallest allowed size",
+			url:  "https://example.com?size=5",
+			configure: func(adapter *querybuilder.GormAdapter) {
+				adapter.AllowedPageSizes(50, 10, 25)
+			},
+			wantPage: 1,
+			wantSize: 10,
+		},
+		{
+			name: "Should reject a size above the maximum",
+			url:  "https://example.com?size=21",
+			configure: func(adapter *querybuilder.GormAdapter) {
+				adapter.MaxPageSize(20).PageSizeMode(querybuilder.PageSizeReject)
+			},
+			wantErr: querybuilder.ErrInvalidPageSize,
+		},
+		{
+			name: "Should reject a size of zero",
+			url:  "https://example.com?size=0",
+			configure: func(adapter *querybuilder.GormAdapter) {
+				adapter.PageSizeMode(querybuilder.PageSizeReject)
+			},
+			wantErr: querybuilder.ErrInvalidPageSize,
+		},
+		{
+			name: "Should reject a size that is not allowed",
+			url:  "https://example.com?size=30",
+			configure: func(adapter *querybuilder.GormAdapter) {
+				adapter.AllowedPageSizes(10, 25, 50).PageSizeMode(querybuilder.PageSizeReject)
+			},
+			wantErr: querybuilder.ErrInvalidPageSize,
+		},
+		{
+			name: "Should accept an allowed size in reject mode",
+			url:  "https://example.com?size=25",
+			configure: func(adapter *querybuilder.GormAdapter) {
+				adapter.AllowedPageSizes(10, 25, 50).PageSizeMode(querybuilder.PageSizeReject)
+			},
+			wantPage: 1,
+			wantSize: 25,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adapter := querybuilder.NewGormAdapter(db.Model(&paginatedUser{})).
+				AllowedSorts([]interface{}{"id"})
+			tt.configure(adapter)
+
+			var users []paginatedUser
+			result, err := adapter.PaginateIntoOnUrl(tt.url, &users)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				status, _ := querybuilder.ErrorResponse(err)
+				assert.Equal(t, 400, status)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tt.wantPage, result.CurrentPage)
+			assert.Equal(t, tt.wantSize, result.PerPage)
+			assert.Len(t, users, tt.wantSize)
+		})
+	}
+}
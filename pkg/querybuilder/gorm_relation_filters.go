@@ -0,0 +1,189 @@
+package querybuilder
+
+import (
+	"fmt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+	"strings"
+)
+
+//relationCondition builds the condition for the column, when the column is qualified with a relationship
+//of the model, e.g. author.country, the condition is wrapped in WHERE EXISTS subqueries following the relationships
+func relationCondition(db *gorm.DB, columnName string, build func(column clause.Column) (clause.Expression, error)) (clause.Expression, error) {
+	parts := strings.Split(columnName, ".")
+	if len(parts) == 1 {
+		return build(clause.Column{Name: columnName})
+	}
+
+	rootSchema := getSchema(db)
+	if rootSchema == nil || findRelationship(rootSchema, parts[0]) == nil {
+		//not a relationship, the column is qualified with a table name
+		return build(clause.Column{Name: columnName})
+	}
+
+	var relationships []*schema.Relationship
+	currentSchema := rootSchema
+	for _, part := range parts[:len(parts)-1] {
+		relationship := findRelationship(currentSchema, part)
+		if relationship == nil {
+			return nil, fmt.Errorf("invalid relationship %s in %s, %w", part, columnName, ErrInvalidFilterQuery)
+		}
+		relationships = append(relationships, relationship)
+		currentSchema = relationship.FieldSchema
+	}
+
+	aliases := make([]string, len(relationships))
+	for index := range relationships {
+		aliases[index] = strings.Join(parts[:index+1], "__")
+	}
+
+	condition, err := build(clause.Column{Table: aliases[len(aliases)-1], Name: parts[len(parts)-1]})
+	if err != nil {
+		return nil, err
+	}
+
+	for index := len(relationships) - 1; index >= 0; index-- {
+		parentTable := clause.CurrentTable
+		if index > 0 {
+			parentTable = aliases[index-1]
+		}
+		condition = newExistsExpression(relationships[index], parentTable, aliases[index], condition)
+	}
+	return condition, nil
+}
+
+func getSchema(db *gorm.DB) *schema.Schema {
+	if db.Statement.Schema != nil {
+		return db.Statement.Schema
+	}
+
+	model := db.Statement.Model
+	if model == nil {
+		model = db.Statement.Dest
+	}
+	if model == nil {
+		return nil
+	}
+
+	if err := db.Statement.Parse(model); err != nil {
+		return nil
+	}
+	return db.Statement.Schema
+}
+
+//findRelationship looks up the relationship by its field name, accepting snake case names such as bank_account
+func findRelationship(s *schema.Schema, name string) *schema.Relationship {
+	if relationship, ok := s.Relationships.Relations[name]; ok {
+		return relationship
+	}
+	if relationship, ok := s.Relationships.Relations[toCamelCase(name)]; ok {
+		return relationship
+	}
+
+	normalizedName := strings.ToLower(strings.ReplaceAll(strings.ReplaceAll(name, "_", ""), "-", ""))
+	for relationshipName, relationship := range s.Relationships.Relations {
+		if strings.ToLower(relationshipName) == normalizedName {
+			return relationship
+		}
+	}
+	return nil
+}
+
+//relationSubquery renders (SELECT selection FROM related alias [JOIN pivot] WHERE keys AND conditions)
+//correlated with the parent table through the relationship
+type relationSubquery struct {
+	selection  string
+	table      clause.Table
+	joinTable  *clause.Table
+	joinOn     []clause.Expression
+	conditions []clause.Expression
+}
+
+//existsExpression renders EXISTS (SELECT 1 FROM related alias [JOIN pivot] WHERE keys AND condition)
+type existsExpression struct {
+	subquery relationSubquery
+}
+
+func newExistsExpression(relationship *schema.Relationship, parentTable string, alias string, condition clause.Expression) existsExpression {
+	subquery := newRelationSubquery(relationship, parentTable, alias, "1")
+	subquery.conditions = append(subquery.conditions, condition)
+	return existsExpression{subquery: subquery}
+}
+
+func (e existsExpression) Build(builder clause.Builder) {
+	builder.WriteString("EXISTS ")
+	e.subquery.Build(builder)
+}
+
+func newRelationSubquery(relationship *schema.Relationship, parentTable string, alias string, selection string) relationSubquery {
+	expression := relationSubquery{
+		selection: selection,
+		table:     clause.Table{Name: relationship.FieldSchema.Table, Alias: alias},
+	}
+
+	if relationship.JoinTable != nil {
+		joinAlias := alias + "__pivot"
+		expression.joinTable = &clause.Table{Name: relationship.JoinTable.Table, Alias: joinAlias}
+		for _, reference := range relationship.References {
+			joinColumn := clause.Column{Table: joinAlias, Name: reference.ForeignKey.DBName}
+			switch {
+			case reference.OwnPrimaryKey:
+				expression.conditions = append(expression.conditions, clause.Eq{
+					Column: joinColumn,
+					Value:  clause.Column{Table: parentTable, Name: reference.PrimaryKey.DBName},
+				})
+			case reference.PrimaryValue != "":
+				expression.conditions = append(expression.conditions, clause.Eq{Column: joinColumn, Value: reference.PrimaryValue})
+			default:
+				expression.joinOn = append(expression.joinOn, clause.Eq{
+					Column: joinColumn,
+					Value:  clause.Column{Table: alias, Name: reference.PrimaryKey.DBName},
+				})
+			}
+		}
+	} else {
+		for _, reference := range relationship.References {
+			switch {
+			case reference.PrimaryValue != "":
+				expression.conditions = append(expression.conditions, clause.Eq{
+					Column: clause.Column{Table: alias, Name: reference.ForeignKey.DBName},
+					Value:  reference.PrimaryValue,
+				})
+			case reference.OwnPrimaryKey:
+				expression.conditions = append(expression.conditions, clause.Eq{
+					Column: clause.Column{Table: alias, Name: reference.ForeignKey.DBName},
+					Value:  clause.Column{Table: parentTable, Name: reference.PrimaryKey.DBName},
+				})
+			default:
+				expression.conditions = append(expression.conditions, clause.Eq{
+					Column: clause.Column{Table: alias, Name: reference.PrimaryKey.DBName},
+					Value:  clause.Column{Table: parentTable, Name: reference.ForeignKey.DBName},
+				})
+			}
+		}
+	}
+
+	return expression
+}
+
+func (e relationSubquery) Build(builder clause.Builder) {
+	builder.WriteString("(SELECT ")
+	builder.WriteString(e.selection)
+	builder.WriteString(" FROM ")
+	builder.WriteQuoted(e.table)
+	if e.joinTable != nil {
+		builder.WriteString(" JOIN ")
+		builder.WriteQuoted(*e.joinTable)
+		builder.WriteString(" ON ")
+		clause.And(e.joinOn...).Build(builder)
+	}
+	builder.WriteString(" WHERE ")
+	for index, condition := range e.conditions {
+		if index > 0 {
+			builder.WriteString(" AND ")
+		}
+		condition.Build(builder)
+	}
+	builder.WriteString(")")
+}
@@ -0,0 +1,140 @@
+package querybuilder_test
+
+import (
+	"testing"
+
+	"github.com/akacokafor/gorm-query-builder/pkg/querybuilder"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type relationCountry struct {
+	ID   uint
+	Code string
+}
+
+type relationProfile struct {
+	ID       uint
+	AuthorID uint
+	Bio      string
+}
+
+type relationAuthor struct {
+	ID        uint
+	Name      string
+	CountryID uint
+	Country   relationCountry
+	Profile   relationProfile `gorm:"foreignKey:AuthorID"`
+}
+
+type relationTag struct {
+	ID   uint
+	Name string
+}
+
+type relationComment struct {
+	ID     uint
+	PostID uint
+	Body   string
+}
+
+type relationPost struct {
+	ID       uint
+	Title    string
+	AuthorID uint
+	Author   relationAuthor
+	Comments []relationComment `gorm:"foreignKey:PostID"`
+	Tags     []relationTag     `gorm:"many2many:relation_post_tags"`
+}
+
+func openRelationDB(t *testing.T) *gorm.DB {
+	golang := relationTag{Name: "go"}
+	rust := relationTag{Name: "rust"}
+	posts := []relationPost{
+		{
+			Title:    "first",
+			Author:   relationAuthor{Name: "Ada", Country: relationCountry{Code: "NG"}, Profile: relationProfile{Bio: "writes compilers"}},
+			Comments: []relationComment{{Body: "great post"}, {Body: "meh"}},
+			Tags:     []relationTag{golang},
+		},
+		{
+			Title:    "second",
+			Author:   relationAuthor{Name: "Linus", Country: relationCountry{Code: "FI"}, Profile: relationProfile{Bio: "writes kernels"}},
+			Comments: []relationComment{{Body: "meh"}},
+			Tags:     []relationTag{rust},
+		},
+		{
+			Title:  "third",
+			Author: relationAuthor{Name: "Grace", Country: relationCountry{Code: "NG"}, Profile: relationProfile{Bio: "writes compilers"}},
+		},
+	}
+	return openSeededDB(t, []interface{}{&relationCountry{}, &relationProfile{}, &relationAuthor{}, &relationTag{}, &relationComment{}, &relationPost{}}, &posts)
+}
+
+func TestGormAdapter_RelationFilters(t *testing.T) {
+	db := openRelationDB(t)
+
+	tests := []struct {
+		name     string
+		model    interface{}
+		filters  []interface{}
+		url      string
+		expected []string
+	}{
+		{
+			name:     "Should filter through a belongs to relationship",
+			filters:  []interface{}{querybuilder.NewGormAllowedFilterExact("author.name")},
+			url:      "https://example.com?filter[author.name]=Ada",
+			expected: []string{"first"},
+		},
+		{
+			name:     "Should filter through nested relationships",
+			filters:  []interface{}{querybuilder.NewGormAllowedFilterExactAlias("country", "author.country.code")},
+			url:      "https://example.com?filter[country]=NG",
+			expected: []string{"first", "third"},
+		},
+		{
+			name:     "Should filter through a has many relationship",
+			filters:  []interface{}{"comments.body"},
+			url:      "https://example.com?filter[comments.body]=great",
+			expected: []string{"first"},
+		},
+		{
+			name:     "Should filter through a many to many relationship",
+			filters:  []interface{}{querybuilder.NewGormAllowedFilterExact("tags.name")},
+			url:      "https://example.com?filter[tags.name]=go,rust",
+			expected: []string{"first", "second"},
+		},
+		{
+			name:     "Should filter through a has one relationship",
+			filters:  []interface{}{querybuilder.NewGormAllowedFilterSearch("author.profile.bio")},
+			url:      "https://example.com?filter[author.profile.bio]=compilers",
+			expected: []string{"first", "third"},
+		},
+		{
+			name:     "Should apply all comparisons of an operator filter to the same related row",
+			filters:  []interface{}{querybuilder.NewGormAllowedFilterOperator("comments.id")},
+			url:      "https://example.com?filter[comments.id][gte]=2&filter[comments.id][lte]=2",
+			expected: []string{"first"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := querybuilder.NewGormAdapter(db.Model(&relationPost{})).
+				AllowedFilters(tt.filters).
+				AllowedSorts([]interface{}{"id"}).
+				DefaultSort(querybuilder.Sort{Name: "id", Ascending: true}).
+				ExecuteOnUrl(tt.url)
+			assert.Nil(t, err)
+
+			var posts []relationPost
+			assert.Nil(t, got.Find(&posts).Error)
+
+			var titles []string
+			for _, post := range posts {
+				titles = append(titles, post.Title)
+			}
+			assert.Equal(t, tt.expected, titles)
+		})
+	}
+}
@@ -0,0 +1,251 @@
+package querybuilder
+
+import (
+	"fmt"
+	"gorm.io/gorm/clause"
+	"strings"
+)
+
+//GormSearchBackend builds the condition matching a search term against columns of a single table
+type GormSearchBackend interface {
+	//Terms splits the q parameter into the terms that must all match, backends that match the whole query return it as a single term
+	Terms(query string) []string
+	Condition(columns []clause.Column, term string) clause.Expression
+}
+
+//GormSearchLike matches every word of the query with LIKE against any of the columns, or the whole query when Phrase is set
+type GormSearchLike struct {
+	Phrase bool
+}
+
+func (s *GormSearchLike) Terms(query string) []string {
+	if s.Phrase {
+		if query = strings.TrimSpace(query); query != "" {
+			return []string{query}
+		}
+		return nil
+	}
+	return strings.Fields(query)
+}
+
+//likeEscaper escapes the LIKE wildcards of a search term, the escape character is ! rather than a backslash because
+//a backslash has to be written differently in MySQL string literals than in the other dialects. [ is a wildcard in SQL Server
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_", "[", "![")
+
+//Condition matches the term literally, % and _ in the term do not act as wildcards
+func (s *GormSearchLike) Condition(columns []clause.Column, term string) clause.Expression {
+	pattern := fmt.Sprintf("%%%s%%", likeEscaper.Replace(term))
+	conditions := make([]clause.Expression, 0, len(columns))
+	for _, column := range columns {
+		conditions = append(conditions, clause.Expr{SQL: "? LIKE ? ESCAPE '!'", Vars: []interface{}{column, pattern}})
+	}
+	return anyOf(conditions)
+}
+
+//NewGormSearchLike creates the default search backend that requires every word of the query to match one of the columns
+func NewGormSearchLike() *GormSearchLike {
+	return &GormSearchLike{}
+}
+
+//GormSearchMySQL matches the query with MATCH ... AGAINST, the columns of a table must be covered by a FULLTEXT index
+type GormSearchMySQL struct {
+	modifier string
+}
+
+func (s *GormSearchMySQL) Terms(query string) []string {
+	return wholeQuery(query)
+}
+
+func (s *GormSearchMySQL) Condition(columns []clause.Column, term string) clause.Expression {
+	vars := make([]interface{}, 0, len(columns)+1)
+	for _, column := range columns {
+		vars = append(vars, column)
+	}
+	vars = append(vars, term)
+	return clause.Expr{
+		SQL:  fmt.Sprintf("MATCH (%s) AGAINST (? %s)", placeholders(len(columns), ","), s.modifier),
+		Vars: vars,
+	}
+}
+
+//NewGormSearchMySQL creates a MySQL full text search in natural language mode
+func NewGormSearchMySQL() *GormSearchMySQL {
+	return &GormSearchMySQL{modifier: "IN NATURAL LANGUAGE MODE"}
+}
+
+//NewGormSearchMySQLBoolean creates a MySQL full text search in boolean mode, the query may use the boolean operators
+func NewGormSearchMySQLBoolean() *GormSearchMySQL {
+	return &GormSearchMySQL{modifier: "IN BOOLEAN MODE"}
+}
+
+//GormSearchPostgres matches the query with to_tsvector(columns) @@ plainto_tsquery(query)
+type GormSearchPostgres struct {
+	language string
+}
+
+func (s *GormSearchPostgres) Terms(query string) []string {
+	return wholeQuery(query)
+}
+
+func (s *GormSearchPostgres) Condition(columns []clause.Column, term string) clause.Expression {
+	var vars []interface{}
+	language := ""
+	if s.language != "" {
+		language = "?::regconfig, "
+		vars = append(vars, s.language)
+	}
+
+	documents := make([]string, 0, len(columns))
+	for _, column := range columns {
+		documents = append(documents, "coalesce(?, '')")
+		vars = append(vars, column)
+	}
+
+	if s.language != "" {
+		vars = append(vars, s.language)
+	}
+	vars = append(vars, term)
+
+	return clause.Expr{
+		SQL:  fmt.Sprintf("to_tsvector(%s%s) @@ plainto_tsquery(%s?)", language, strings.Join(documents, " || ' ' || "), language),
+		Vars: vars,
+	}
+}
+
+//NewGormSearchPostgres creates a Postgres full text search, an empty language uses the default text search configuration
+func NewGormSearchPostgres(language string) *GormSearchPostgres {
+	return &GormSearchPostgres{language: language}
+}
+
+//GormSearchSQLiteFTS5 matches the query against an FTS5 table whose rowid is the rowid of the searched table,
+//e.g. an external content table, restricted to the searched columns. The FTS5 table indexes a single table,
+//so only columns of that table should be searched
+type GormSearchSQLiteFTS5 struct {
+	table string
+}
+
+func (s *GormSearchSQLiteFTS5) Terms(query string) []string {
+	return wholeQuery(query)
+}
+
+func (s *GormSearchSQLiteFTS5) Condition(columns []clause.Column, term string) clause.Expression {
+	names := make([]string, 0, len(columns))
+	for _, column := range columns {
+		names = append(names, column.Name[strings.LastIndex(column.Name, ".")+1:])
+	}
+
+	//every word is quoted so that the query can not use the FTS5 query syntax
+	words := strings.Fields(term)
+	for index, word := range words {
+		words[index] = `"` + strings.ReplaceAll(word, `"`, `""`) + `"`
+	}
+
+	rowTable := clause.CurrentTable
+	if len(columns) > 0 && columns[0].Table != "" {
+		rowTable = columns[0].Table
+	}
+	return clause.Expr{
+		SQL: "? IN (SELECT rowid FROM ? WHERE ? MATCH ?)",
+		Vars: []interface{}{
+			clause.Column{Table: rowTable, Name: "rowid"},
+			clause.Table{Name: s.table},
+			clause.Table{Name: s.table},
+			fmt.Sprintf("{%s} : %s", strings.Join(names, " "), strings.Join(words, " ")),
+		},
+	}
+}
+
+//NewGormSearchSQLiteFTS5 creates a search against the FTS5 table
+func NewGormSearchSQLiteFTS5(table string) *GormSearchSQLiteFTS5 {
+	return &GormSearchSQLiteFTS5{table: table}
+}
+
+//AllowedSearch sets the columns the q parameter searches, columns of relationships are qualified with the relationship, e.g. author.name
+func (g *GormAdapter) AllowedSearch(columns ...string) *GormAdapter {
+	g.searchColumns = columns
+	return g
+}
+
+//SearchBackend sets how the q parameter is matched against the search columns, defaults to NewGormSearchLike
+func (g *GormAdapter) SearchBackend(backend GormSearchBackend) *GormAdapter {
+	g.searchBackend = backend
+	return g
+}
+
+//searchGroup holds the search columns of one table, they are matched together so that a full text index can cover them
+type searchGroup struct {
+	path    string
+	columns []string
+}
+
+//applySearch requires each term of the query to match one of the search columns
+func (g *GormAdapter) applySearch(query string) error {
+	backend := g.searchBackend
+	if backend == nil {
+		backend = NewGormSearchLike()
+	}
+
+	groups := groupSearchColumns(g.searchColumns)
+	var termConditions []clause.Expression
+	for _, term := range backend.Terms(query) {
+		var conditions []clause.Expression
+		for _, group := range groups {
+			group := group
+			condition, err := relationCondition(g.db, group.columns[0], func(column clause.Column) (clause.Expression, error) {
+				columns := make([]clause.Column, 0, len(group.columns))
+				for _, name := range group.columns {
+					if column.Table == "" {
+						columns = append(columns, clause.Column{Name: name})
+					} else {
+						columns = append(columns, clause.Column{Table: column.Table, Name: name[len(group.path)+1:]})
+					}
+				}
+				return backend.Condition(columns, term), nil
+			})
+			if err != nil {
+				return err
+			}
+			conditions = append(conditions, condition)
+		}
+		termConditions = append(termConditions, anyOf(conditions))
+	}
+
+	switch len(termConditions) {
+	case 0:
+	case 1:
+		g.db.Where(termConditions[0])
+	default:
+		g.db.Where(clause.And(termConditions...))
+	}
+	return nil
+}
+
+//groupSearchColumns groups the columns by their relationship path, keeping the order they were configured in
+func groupSearchColumns(columns []string) []searchGroup {
+	var groups []searchGroup
+	indexes := make(map[string]int)
+	for _, column := range columns {
+		path := ""
+		if index := strings.LastIndex(column, "."); index >= 0 {
+			path = column[:index]
+		}
+		if index, ok := indexes[path]; ok {
+			groups[index].columns = append(groups[index].columns, column)
+			continue
+		}
+		indexes[path] = len(groups)
+		groups = append(groups, searchGroup{path: path, columns: []string{column}})
+	}
+	return groups
+}
+
+func wholeQuery(query string) []string {
+	if query = strings.TrimSpace(query); query != "" {
+		return []string{query}
+	}
+	return nil
+}
+
+func placeholders(count int, separator string) string {
+	return strings.TrimSuffix(strings.Repeat("?"+separator, count), separator)
+}
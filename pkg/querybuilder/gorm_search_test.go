@@ -0,0 +1,155 @@
+package querybuilder_test
+
+import (
+	"testing"
+
+	"github.com/akacokafor/gorm-query-builder/pkg/querybuilder"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestGormAdapter_AllowedSearch(t *testing.T) {
+	db := openRelationDB(t)
+
+	tests := []struct {
+		name     string
+		columns  []string
+		backend  querybuilder.GormSearchBackend
+		url      string
+		expected []string
+	}{
+		{
+			name:     "Should search the root columns",
+			columns:  []string{"title"},
+			url:      "https://example.com?q=ir",
+			expected: []string{"first", "third"},
+		},
+		{
+			name:     "Should search the columns of relationships",
+			columns:  []string{"title", "author.name", "comments.body"},
+			url:      "https://example.com?q=Ada",
+			expected: []string{"first"},
+		},
+		{
+			name:     "Should require every word to match one of the columns",
+			columns:  []string{"title", "author.name", "comments.body"},
+			url:      "https://example.com?q=great+first",
+			expected: []string{"first"},
+		},
+		{
+			name:     "Should not match when a word matches none of the columns",
+			columns:  []string{"title", "author.name", "comments.body"},
+			url:      "https://example.com?q=great+second",
+			expected: nil,
+		},
+		{
+			name:     "Should search nested relationships",
+			columns:  []string{"author.country.code", "author.profile.bio"},
+			url:      "https://example.com?q=FI",
+			expected: []string{"second"},
+		},
+		{
+			name:     "Should match the whole query as a phrase",
+			columns:  []string{"comments.body"},
+			backend:  &querybuilder.GormSearchLike{Phrase: true},
+			url:      "https://example.com?q=post+great",
+			expected: nil,
+		},
+		{
+			name:     "Should match an underscore literally",
+			columns:  []string{"title"},
+			url:      "https://example.com?q=_",
+			expected: nil,
+		},
+		{
+			name:     "Should match a percent sign literally",
+			columns:  []string{"title", "author.name"},
+			url:      "https://example.com?q=%25",
+			expected: nil,
+		},
+		{
+			name:     "Should ignore a blank query",
+			columns:  []string{"title"},
+			url:      "https://example.com?q=+",
+			expected: []string{"first", "second", "third"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adapter := querybuilder.NewGormAdapter(db.Model(&relationPost{})).
+				AllowedSearch(tt.columns...).
+				AllowedSorts([]interface{}{"id"}).
+				DefaultSort(querybuilder.Sort{Name: "id", Ascending: true})
+			if tt.backend != nil {
+				adapter.SearchBackend(tt.backend)
+			}
+
+			got, err := adapter.ExecuteOnUrl(tt.url)
+			assert.Nil(t, err)
+
+			var posts []relationPost
+			assert.Nil(t, got.Find(&posts).Error)
+
+			var titles []string
+			for _, post := range posts {
+				titles = append(titles, post.Title)
+			}
+			assert.Equal(t, tt.expected, titles)
+		})
+	}
+}
+
+func TestGormAdapter_AllowedSearch_FullText(t *testing.T) {
+	tests := []struct {
+		name      string
+		dialector gorm.Dialector
+		backend   querybuilder.GormSearchBackend
+		columns   []string
+		expected  string
+	}{
+		{
+			name: "Should match against the full text index in MySQL",
+			dialector: mysql.New(mysql.Config{
+				DSN:                       "user:pass@tcp(127.0.0.1:3306)/db",
+				SkipInitializeWithVersion: true,
+			}),
+			backend:  querybuilder.NewGormSearchMySQL(),
+			columns:  []string{"title", "author.name"},
+			expected: "SELECT * FROM `relation_posts` WHERE (MATCH (`title`) AGAINST ('golang tips' IN NATURAL LANGUAGE MODE) OR EXISTS (SELECT 1 FROM `relation_authors` `author` WHERE `author`.`id` = `relation_posts`.`author_id` AND MATCH (`author`.`name`) AGAINST ('golang tips' IN NATURAL LANGUAGE MODE)))",
+		},
+		{
+			name:      "Should match the text search vector in Postgres",
+			dialector: postgres.Open("host=127.0.0.1 user=user password=pass dbname=db"),
+			backend:   querybuilder.NewGormSearchPostgres("english"),
+			columns:   []string{"title", "author.name"},
+			expected:  `SELECT * FROM "relation_posts" WHERE ((to_tsvector('english'::regconfig, coalesce("title", '')) @@ plainto_tsquery('english'::regconfig, 'golang tips')) OR EXISTS (SELECT 1 FROM "relation_authors" "author" WHERE "author"."id" = "relation_posts"."author_id" AND to_tsvector('english'::regconfig, coalesce("author"."name", '')) @@ plainto_tsquery('english'::regconfig, 'golang tips')))`,
+		},
+		{
+			name:      "Should match the FTS5 table in SQLite",
+			dialector: sqlite.Open(":memory:"),
+			backend:   querybuilder.NewGormSearchSQLiteFTS5("relation_posts_fts"),
+			columns:   []string{"title"},
+			expected:  "SELECT * FROM `relation_posts` WHERE `relation_posts`.`rowid` IN (SELECT rowid FROM `relation_posts_fts` WHERE `relation_posts_fts` MATCH \"{title} : \\\"golang\\\" \\\"tips\\\"\")",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, err := gorm.Open(tt.dialector, &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := querybuilder.NewGormAdapter(db.Model(&relationPost{})).
+				AllowedSearch(tt.columns...).
+				SearchBackend(tt.backend).
+				ExecuteOnUrl("https://example.com?q=golang+tips")
+			assert.Nil(t, err)
+
+			stmt := got.Find(&[]relationPost{}).Statement
+			assert.Equal(t, tt.expected, db.Dialector.Explain(stmt.SQL.String(), stmt.Vars...))
+		})
+	}
+}
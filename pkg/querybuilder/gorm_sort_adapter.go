@@ -4,9 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"gorm.io/gorm"
-	"log"
-	"reflect"
-	"strings"
+	"gorm.io/gorm/clause"
 )
 
 type GormAllowedSort interface {
@@ -14,14 +12,13 @@ type GormAllowedSort interface {
 	Execute(db *gorm.DB, options OptionsInterface) error
 }
 
-func (g *GormAdapter) validateSorts(instance OptionsInterface) error {
+func (g *GormAdapter) validateSorts(instance OptionsInterface, validation *QueryValidationError) error {
 
 	if len( g.sortWhitelist) == 0 {
 		return nil
 	}
 
 	for _, entry := range g.sortWhitelist {
-		log.Printf("entry = %s", reflect.TypeOf(entry).String())
 		_, isString := entry.(string)
 		_, isAllowedFilter := entry.(GormAllowedSort)
 		if !isAllowedFilter && !isString {
@@ -31,7 +28,14 @@ func (g *GormAdapter) validateSorts(instance OptionsInterface) error {
 
 	for _, name := range instance.GetSort() {
 		if !g.isValidSortName(name) {
-			return fmt.Errorf("invalid sort key %s, %w", name.GetName(), ErrInvalidSortQuery)
+			validation.Add(&QueryParameterError{
+				Code:      ErrorCodeInvalidSort,
+				Parameter: "sort",
+				Value:     name.GetName(),
+				Allowed:   g.getSortNames(g.sortWhitelist),
+				Detail:    fmt.Sprintf("invalid sort key %s", name.GetName()),
+				Err:       ErrInvalidSortQuery,
+			}, ErrorCodeInvalidSort, "sort")
 		}
 	}
 
@@ -70,50 +74,24 @@ func (g *GormAdapter) applySorts(instance OptionsInterface) error {
 	}
 
 	if len(g.sortWhitelist) == 0 {
-		orderStr := ""
-		separator := ""
-		for index, val := range sortableList {
-			if index > 0 && separator != "," {
-				separator = ","
-			}
-			direction := "ASC"
-			if !val.IsAscending() {
-				direction = "DESC"
-			}
-			orderStr = strings.TrimSpace(fmt.Sprintf("%s%s `%s` %s",orderStr, separator,val.GetName(),direction))
-		}
-		if orderStr != "" {
-			g.db.Order(orderStr)
+		for _, val := range sortableList {
+			g.orderBy(val)
 		}
 		return nil
 	}
 
-
-	orderStr := ""
-	separator := ""
-	for index, sortEntry := range sortableList {
-		if index > 0 && separator != "," {
-			separator = ","
-		}
+	for _, sortEntry := range sortableList {
 		for _, sortWhiteListEntry := range g.sortWhitelist {
 			if _k, ok := sortWhiteListEntry.(string); ok {
 				if _k == sortEntry.GetName() {
-					direction := "ASC"
-					if !sortEntry.IsAscending() {
-						direction = "DESC"
-					}
-
-					orderStr = strings.TrimSpace(fmt.Sprintf("%s%s `%s` %s",orderStr, separator,sortEntry.GetName(),direction))
-					if orderStr[:1] == "," {
-						orderStr = orderStr[1:]
-					}
+					g.orderBy(sortEntry)
 				}
 			}
 
 			if op, ok := sortWhiteListEntry.(GormAllowedSort); ok {
 				for _, _k := range op.Names() {
 					if _k == sortEntry.GetName() {
-						if err := op.Execute(g.db,instance); err != nil {
+						if err := op.Execute(g.db, sortOptions{OptionsInterface: instance, sorts: sortableList}); err != nil {
 							return err
 						}
 					}
@@ -122,8 +100,29 @@ func (g *GormAdapter) applySorts(instance OptionsInterface) error {
 		}
 	}
 
-	if orderStr != "" {
-		g.db.Order(orderStr)
-	}
 	return nil
-}
\ No newline at end of file
+}
+
+func (g *GormAdapter) orderBy(sort Sortable) {
+	orderBy(g.db, sort.GetName(), sort.IsAscending())
+}
+
+//orderBy appends the sort to the query, quoting the column with the identifier quotes of the current dialect
+func orderBy(db *gorm.DB, columnName string, ascending bool) {
+	direction := "ASC"
+	if !ascending {
+		direction = "DESC"
+	}
+	column := db.Statement.Quote(clause.Column{Name: columnName})
+	db.Order(fmt.Sprintf("%s %s", column, direction))
+}
+
+//sortOptions exposes the sorts being applied, including the default sort, to GormAllowedSort implementations
+type sortOptions struct {
+	OptionsInterface
+	sorts []Sortable
+}
+
+func (s sortOptions) GetSort() []Sortable {
+	return s.sorts
+}
@@ -31,3 +31,36 @@ func NewGormAllowedSortCustom(propName string, sorter GormAllowedSorter) *GormAl
 		sorter: sorter,
 	}
 }
+
+//GormAllowedSortAlias exposes a column as sort=alias without revealing the column name
+type GormAllowedSortAlias struct {
+	propName   string
+	columnName string
+}
+
+func (g *GormAllowedSortAlias) Names() []string {
+	return []string{
+		g.propName,
+	}
+}
+
+func (g *GormAllowedSortAlias) ColumnName() string {
+	return g.columnName
+}
+
+func (g *GormAllowedSortAlias) Execute(db *gorm.DB, options OptionsInterface) error {
+	for _, sort := range options.GetSort() {
+		if sort.GetName() == g.propName {
+			orderBy(db, g.columnName, sort.IsAscending())
+			return nil
+		}
+	}
+	return nil
+}
+
+func NewGormAllowedSortAlias(alias string, columnName string) *GormAllowedSortAlias {
+	return &GormAllowedSortAlias{
+		propName:   alias,
+		columnName: columnName,
+	}
+}
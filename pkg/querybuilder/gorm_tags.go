@@ -0,0 +1,119 @@
+package querybuilder
+
+import (
+	"fmt"
+	"gorm.io/gorm"
+	"strings"
+)
+
+//TagName is the struct tag NewGormAdapterFor reads the whitelists from
+const TagName = "qb"
+
+//NewGormAdapterFor creates an adapter for the model with the whitelists built from its qb struct tags, e.g.
+//
+//	type User struct {
+//		ID     uint   `qb:"filter=exact,sort,field"`
+//		Name   string `qb:"filter,sort,field"`
+//		Email  string `qb:"filter=exact,name=mail"`
+//		Wallet Wallet `qb:"include"`
+//	}
+//
+//filter accepts exact, partial (the default) and operator, name sets the public name used in the filter, sort and
+//include query parameters. Fields are always requested by column, so name can not be combined with field.
+//The whitelists can still be replaced with AllowedFilters, AllowedSorts, AllowedFields and AllowedIncludes
+func NewGormAdapterFor(db *gorm.DB, model interface{}) (*GormAdapter, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return nil, err
+	}
+
+	var filters, sorts, fields, includes []interface{}
+	for _, field := range stmt.Schema.Fields {
+		tag, ok := field.Tag.Lookup(TagName)
+		if !ok || tag == "-" {
+			continue
+		}
+
+		options, err := parseTag(tag)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", stmt.Schema.Name, field.Name, err)
+		}
+
+		if _, isInclude := options["include"]; isInclude {
+			relationship, isRelationship := stmt.Schema.Relationships.Relations[field.Name]
+			if !isRelationship {
+				return nil, fmt.Errorf("%s.%s: include requires a relationship field", stmt.Schema.Name, field.Name)
+			}
+			name := options["name"]
+			if name == "" {
+				name = db.NamingStrategy.ColumnName("", field.Name)
+			}
+			includes = append(includes, NewGormAllowedIncludeAlias(name, relationship.Name))
+			continue
+		}
+
+		if field.DBName == "" {
+			return nil, fmt.Errorf("%s.%s: only include can be used on fields without a column", stmt.Schema.Name, field.Name)
+		}
+
+		name := options["name"]
+		if _, isField := options["field"]; isField && name != "" {
+			return nil, fmt.Errorf("%s.%s: name can not be used with field, fields are requested by column", stmt.Schema.Name, field.Name)
+		}
+		if name == "" {
+			name = field.DBName
+		}
+
+		if filterType, isFilter := options["filter"]; isFilter {
+			switch filterType {
+			case "", "partial":
+				filters = append(filters, NewGormAllowedFilterSearchAlias(name, field.DBName))
+			case "exact":
+				filters = append(filters, NewGormAllowedFilterExactAlias(name, field.DBName))
+			case "operator":
+				filters = append(filters, NewGormAllowedFilterOperatorAlias(name, field.DBName))
+			default:
+				return nil, fmt.Errorf("%s.%s: unknown filter type %s", stmt.Schema.Name, field.Name, filterType)
+			}
+		}
+
+		if _, isSort := options["sort"]; isSort {
+			sorts = append(sorts, NewGormAllowedSortAlias(name, field.DBName))
+		}
+
+		if _, isField := options["field"]; isField {
+			fields = append(fields, field.DBName)
+		}
+	}
+
+	adapter := NewGormAdapter(db.Model(model)).
+		AllowedFilters(filters).
+		AllowedSorts(sorts).
+		AllowedFields(fields).
+		AllowedIncludes(includes)
+	adapter.model = model
+	return adapter, nil
+}
+
+func parseTag(tag string) (map[string]string, error) {
+	options := map[string]string{}
+	for _, option := range strings.Split(tag, ",") {
+		option = strings.TrimSpace(option)
+		if option == "" {
+			continue
+		}
+
+		key, value := option, ""
+		if index := strings.Index(option, "="); index >= 0 {
+			key, value = strings.TrimSpace(option[:index]), strings.TrimSpace(option[index+1:])
+		}
+
+		switch key {
+		case "filter", "sort", "field", "include", "name":
+			options[key] = value
+		default:
+			return nil, fmt.Errorf("unknown %s tag option %s", TagName, key)
+		}
+	}
+	return options, nil
+}
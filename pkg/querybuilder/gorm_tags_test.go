@@ -0,0 +1,155 @@
+package querybuilder_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/akacokafor/gorm-query-builder/pkg/querybuilder"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type taggedWallet struct {
+	ID           uint
+	TaggedUserID uint
+	Balance      int
+}
+
+type taggedUser struct {
+	ID      uint   `qb:"filter=operator,sort,field"`
+	Name    string `qb:"filter,sort,field"`
+	Email   string `qb:"filter=exact,name=mail"`
+	Secret  string
+	Wallets []taggedWallet `qb:"include"`
+}
+
+type invalidTaggedUser struct {
+	ID   uint `qb:"filter=fuzzy"`
+	Name string
+}
+
+type aliasedFieldTaggedUser struct {
+	ID    uint
+	Email string `qb:"field,name=mail"`
+}
+
+func openTaggedDB(t *testing.T) *gorm.DB {
+	users := []taggedUser{
+		{Name: "Ada", Email: "ada@example.com", Secret: "a", Wallets: []taggedWallet{{Balance: 10}}},
+		{Name: "Grace", Email: "grace@example.com", Secret: "b", Wallets: []taggedWallet{{Balance: 20}}},
+		{Name: "Adaeze", Email: "adaeze@example.com", Secret: "c"},
+	}
+	return openSeededDB(t, []interface{}{&taggedUser{}, &taggedWallet{}}, &users)
+}
+
+func TestNewGormAdapterFor(t *testing.T) {
+	db := openTaggedDB(t)
+
+	tests := []struct {
+		name      string
+		url       string
+		configure func(adapter *querybuilder.GormAdapter)
+		wantErr   error
+		validate  func(t *testing.T, users []taggedUser)
+	}{
+		{
+			name: "Should apply a partial filter from the tags",
+			url:  "https://example.com?filter[name]=ada&sort=id",
+			validate: func(t *testing.T, users []taggedUser) {
+				assert.Len(t, users, 2)
+				assert.Equal(t, "Ada", users[0].Name)
+				assert.Equal(t, "Adaeze", users[1].Name)
+			},
+		},
+		{
+			name: "Should apply an exact filter under its public name",
+			url:  "https://example.com?filter[mail]=grace@example.com",
+			validate: func(t *testing.T, users []taggedUser) {
+				assert.Len(t, users, 1)
+				assert.Equal(t, "Grace", users[0].Name)
+			},
+		},
+		{
+			name: "Should apply an operator filter from the tags",
+			url:  "https://example.com?filter[id][gt]=1&sort=-id",
+			validate: func(t *testing.T, users []taggedUser) {
+				assert.Len(t, users, 2)
+				assert.Equal(t, "Adaeze", users[0].Name)
+				assert.Equal(t, "Grace", users[1].Name)
+			},
+		},
+		{
+			name: "Should preload an include from the tags",
+			url:  "https://example.com?include=wallets&sort=id",
+			validate: func(t *testing.T, users []taggedUser) {
+				assert.Len(t, users, 3)
+				assert.Len(t, users[0].Wallets, 1)
+				assert.Equal(t, 10, users[0].Wallets[0].Balance)
+			},
+		},
+		{
+			name: "Should select the fields from the tags",
+			url:  "https://example.com?fields[tagged_users]=id,name&sort=id",
+			validate: func(t *testing.T, users []taggedUser) {
+				assert.Equal(t, "Ada", users[0].Name)
+				assert.Empty(t, users[0].Email)
+			},
+		},
+		{
+			name:    "Should reject a filter without a tag",
+			url:     "https://example.com?filter[secret]=a",
+			wantErr: querybuilder.ErrInvalidFilterQuery,
+		},
+		{
+			name:    "Should reject a sort without a tag",
+			url:     "https://example.com?sort=email",
+			wantErr: querybuilder.ErrInvalidSortQuery,
+		},
+		{
+			name:    "Should reject a field without a tag",
+			url:     "https://example.com?fields[tagged_users]=secret",
+			wantErr: querybuilder.ErrInvalidFieldQuery,
+		},
+		{
+			name: "Should let explicit whitelists override the tags",
+			url:  "https://example.com?filter[secret]=b",
+			configure: func(adapter *querybuilder.GormAdapter) {
+				adapter.AllowedFilters([]interface{}{querybuilder.NewGormAllowedFilterExact("secret")})
+			},
+			validate: func(t *testing.T, users []taggedUser) {
+				assert.Len(t, users, 1)
+				assert.Equal(t, "Grace", users[0].Name)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adapter, err := querybuilder.NewGormAdapterFor(db, &taggedUser{})
+			assert.Nil(t, err)
+			if tt.configure != nil {
+				tt.configure(adapter)
+			}
+
+			got, err := adapter.ExecuteOnUrl(tt.url)
+			if tt.wantErr != nil {
+				assert.True(t, errors.Is(err, tt.wantErr), "expected %v, got %v", tt.wantErr, err)
+				return
+			}
+			assert.Nil(t, err)
+
+			var users []taggedUser
+			assert.Nil(t, got.Find(&users).Error)
+			tt.validate(t, users)
+		})
+	}
+}
+
+func TestNewGormAdapterFor_InvalidTags(t *testing.T) {
+	db := openTaggedDB(t)
+
+	_, err := querybuilder.NewGormAdapterFor(db, &invalidTaggedUser{})
+	assert.NotNil(t, err)
+
+	_, err = querybuilder.NewGormAdapterFor(db, &aliasedFieldTaggedUser{})
+	assert.NotNil(t, err)
+}
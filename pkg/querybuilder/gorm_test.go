@@ -297,7 +297,7 @@ func TestGormAdapter_ExecuteOnUrl(t *testing.T) {
 				sqlString := db.Dialector.Explain(stmt.SQL.String(), stmt.Vars...)
 				assert.Nil(t, err)
 				assert.NotNil(t, stmt)
-				assert.Contains(t, sqlString, "ORDER BY LENGTH(name) DESC, `created_at` ASC")
+				assert.Contains(t, sqlString, "ORDER BY LENGTH(name) DESC,`created_at` ASC")
 			},
 		},
 
@@ -321,6 +321,268 @@ func TestGormAdapter_ExecuteOnUrl(t *testing.T) {
 				assert.Contains(t, f.instance.GetRelationships(), "Wallet.BankAccount")
 			},
 		},
+		{
+			name: "Should throw error when field supplied is not in allowed fields",
+			fields: fields{
+				db: db,
+				fieldsWhiteList: []interface{}{
+					"id",
+					"name",
+				},
+			},
+			args: args{
+				url: "https://example.com?fields[users]=id,password",
+			},
+			validator: func(t *testing.T, f *fields, db *gorm.DB, err error) {
+				assert.NotNil(t, err)
+				assert.True(t, errors.Is(err, querybuilder.ErrInvalidFieldQuery))
+			},
+		},
+		{
+			name: "Should successfully select only the requested fields",
+			fields: fields{
+				db: db,
+				fieldsWhiteList: []interface{}{
+					"id",
+					"users.name",
+					"email",
+				},
+			},
+			args: args{
+				url: "https://example.com?fields[users]=id,name",
+			},
+			validator: func(t *testing.T, f *fields, db *gorm.DB, err error) {
+				stmt := db.Scan(&map[string]interface{}{}).Statement
+				sqlString := db.Dialector.Explain(stmt.SQL.String(), stmt.Vars...)
+				assert.Nil(t, err)
+				assert.Contains(t, sqlString, "SELECT `id`,`name` FROM `users`")
+			},
+		},
+		{
+			name: "Should successfully restrict the fields of included relationships",
+			fields: fields{
+				db: db,
+				fieldsWhiteList: []interface{}{
+					"id",
+					"wallet.id",
+					"wallet.balance",
+				},
+				includesWhitelist: []interface{}{
+					"wallet",
+				},
+			},
+			args: args{
+				url: "https://example.com?include=wallet&fields[wallet]=id,balance",
+			},
+			validator: func(t *testing.T, f *fields, db *gorm.DB, err error) {
+				assert.Nil(t, err)
+				assert.Contains(t, f.instance.GetRelationships(), "Wallet")
+				assert.Len(t, db.Statement.Preloads["Wallet"], 1)
+			},
+		},
+		{
+			name: "Should successfully apply comparison operators from nested filter keys",
+			fields: fields{
+				db: db,
+				filtersWhitelist: []interface{}{
+					querybuilder.NewGormAllowedFilterOperator("price", querybuilder.FilterOperatorGte, querybuilder.FilterOperatorLt),
+				},
+			},
+			args: args{
+				url: "https://example.com?filter[price][gte]=100&filter[price][lt]=500",
+			},
+			validator: func(t *testing.T, f *fields, db *gorm.DB, err error) {
+				stmt := db.Scan(&map[string]interface{}{}).Statement
+				sqlString := db.Dialector.Explain(stmt.SQL.String(), stmt.Vars...)
+				assert.Nil(t, err)
+				assert.Contains(t, sqlString, "`price` >= 100 AND `price` < 500")
+			},
+		},
+		{
+			name: "Should successfully apply between operator from prefixed filter value",
+			fields: fields{
+				db: db,
+				filtersWhitelist: []interface{}{
+					querybuilder.NewGormAllowedFilterOperator("created_at"),
+				},
+			},
+			args: args{
+				url: "https://example.com?filter[created_at]=between:2024-01-01,2024-02-01",
+			},
+			validator: func(t *testing.T, f *fields, db *gorm.DB, err error) {
+				stmt := db.Scan(&map[string]interface{}{}).Statement
+				sqlString := db.Dialector.Explain(stmt.SQL.String(), stmt.Vars...)
+				assert.Nil(t, err)
+				assert.Contains(t, sqlString, "`created_at` BETWEEN \"2024-01-01\" AND \"2024-02-01\"")
+			},
+		},
+		{
+			name: "Should throw error when operator supplied is not allowed for the filter",
+			fields: fields{
+				db: db,
+				filtersWhitelist: []interface{}{
+					querybuilder.NewGormAllowedFilterOperator("price", querybuilder.FilterOperatorGte),
+				},
+			},
+			args: args{
+				url: "https://example.com?filter[price]=ne:100",
+			},
+			validator: func(t *testing.T, f *fields, db *gorm.DB, err error) {
+				assert.NotNil(t, err)
+				assert.True(t, errors.Is(err, querybuilder.ErrInvalidFilterOperator))
+			},
+		},
+		{
+			name: "Should throw error when operators are supplied to partial filters",
+			fields: fields{
+				db: db,
+				filtersWhitelist: []interface{}{
+					"name",
+				},
+			},
+			args: args{
+				url: "https://example.com?filter[name][gte]=1",
+			},
+			validator: func(t *testing.T, f *fields, db *gorm.DB, err error) {
+				assert.True(t, errors.Is(err, querybuilder.ErrInvalidFilterOperator))
+			},
+		},
+		{
+			name: "Should throw error when operators are supplied to exact filters",
+			fields: fields{
+				db: db,
+				filtersWhitelist: []interface{}{
+					querybuilder.NewGormAllowedFilterExact("status"),
+				},
+			},
+			args: args{
+				url: "https://example.com?filter[status][gte]=1",
+			},
+			validator: func(t *testing.T, f *fields, db *gorm.DB, err error) {
+				assert.True(t, errors.Is(err, querybuilder.ErrInvalidFilterOperator))
+			},
+		},
+		{
+			name: "Should throw error when operators are supplied without a filter white list",
+			fields: fields{
+				db: db,
+			},
+			args: args{
+				url: "https://example.com?filter[name][gte]=1",
+			},
+			validator: func(t *testing.T, f *fields, db *gorm.DB, err error) {
+				assert.True(t, errors.Is(err, querybuilder.ErrInvalidFilterOperator))
+			},
+		},
+		{
+			name: "Should successfully apply IN for exact filters with multiple values",
+			fields: fields{
+				db: db,
+				filtersWhitelist: []interface{}{
+					querybuilder.NewGormAllowedFilterExact("status"),
+				},
+			},
+			args: args{
+				url: "https://example.com?filter[status]=open&filter[status]=closed&filter[status]=pending",
+			},
+			validator: func(t *testing.T, f *fields, db *gorm.DB, err error) {
+				stmt := db.Scan(&map[string]interface{}{}).Statement
+				sqlString := db.Dialector.Explain(stmt.SQL.String(), stmt.Vars...)
+				assert.Nil(t, err)
+				assert.Contains(t, sqlString, "`status` IN (\"open\",\"closed\",\"pending\")")
+			},
+		},
+		{
+			name: "Should successfully OR partial filters with multiple values",
+			fields: fields{
+				db: db,
+				filtersWhitelist: []interface{}{
+					querybuilder.NewGormAllowedFilterExact("is_completed"),
+					"name",
+				},
+			},
+			args: args{
+				url: "https://example.com?filter[name]=john,jane&filter[is_completed]=1",
+			},
+			validator: func(t *testing.T, f *fields, db *gorm.DB, err error) {
+				stmt := db.Scan(&map[string]interface{}{}).Statement
+				sqlString := db.Dialector.Explain(stmt.SQL.String(), stmt.Vars...)
+				assert.Nil(t, err)
+				assert.Contains(t, sqlString, "(`name` LIKE \"%john%\" OR `name` LIKE \"%jane%\")")
+				assert.Contains(t, sqlString, "`is_completed` = 1")
+			},
+		},
+		{
+			name: "Should successfully apply scope filters with the filter value",
+			fields: fields{
+				db: db,
+				filtersWhitelist: []interface{}{
+					querybuilder.NewGormAllowedFilterScope("overdue", func(db *gorm.DB, value interface{}) *gorm.DB {
+						if value == true {
+							return db.Where("due_at < CURRENT_TIMESTAMP")
+						}
+						return db
+					}),
+					querybuilder.NewGormAllowedFilterScope("assigned_to", func(db *gorm.DB, value interface{}) *gorm.DB {
+						return db.Where("assignee_id IN ?", value)
+					}),
+				},
+			},
+			args: args{
+				url: "https://example.com?filter[overdue]=true&filter[assigned_to]=4,5",
+			},
+			validator: func(t *testing.T, f *fields, db *gorm.DB, err error) {
+				stmt := db.Scan(&map[string]interface{}{}).Statement
+				sqlString := db.Dialector.Explain(stmt.SQL.String(), stmt.Vars...)
+				assert.Nil(t, err)
+				assert.Contains(t, sqlString, "due_at < CURRENT_TIMESTAMP")
+				assert.Contains(t, sqlString, "assignee_id IN (4,5)")
+			},
+		},
+		{
+			name: "Should successfully map filter, sort and include aliases to internal names",
+			fields: fields{
+				db: db,
+				filtersWhitelist: []interface{}{
+					querybuilder.NewGormAllowedFilterExactAlias("author", "user_id"),
+					querybuilder.NewGormAllowedFilterSearchAlias("headline", "title"),
+				},
+				sortWhitelist: []interface{}{
+					querybuilder.NewGormAllowedSortAlias("published", "published_at"),
+				},
+				includesWhitelist: []interface{}{
+					querybuilder.NewGormAllowedIncludeAlias("writer", "Author"),
+				},
+				defaultSort: querybuilder.Sort{Name: "published", Ascending: false},
+			},
+			args: args{
+				url: "https://example.com?filter[author]=5&filter[headline]=go&include=writer",
+			},
+			validator: func(t *testing.T, f *fields, db *gorm.DB, err error) {
+				stmt := db.Scan(&map[string]interface{}{}).Statement
+				sqlString := db.Dialector.Explain(stmt.SQL.String(), stmt.Vars...)
+				assert.Nil(t, err)
+				assert.Contains(t, sqlString, "`user_id` = 5")
+				assert.Contains(t, sqlString, "`title` LIKE \"%go%\"")
+				assert.Contains(t, sqlString, "ORDER BY `published_at` DESC")
+				assert.Equal(t, []string{"Author"}, f.instance.GetRelationships())
+			},
+		},
+		{
+			name: "Should throw error when the internal name of an alias is supplied",
+			fields: fields{
+				db: db,
+				filtersWhitelist: []interface{}{
+					querybuilder.NewGormAllowedFilterExactAlias("author", "user_id"),
+				},
+			},
+			args: args{
+				url: "https://example.com?filter[user_id]=5",
+			},
+			validator: func(t *testing.T, f *fields, db *gorm.DB, err error) {
+				assert.True(t, errors.Is(err, querybuilder.ErrInvalidFilterQuery))
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,336 @@
+package querybuilder
+
+import (
+	"fmt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+//JSONAPIDocument is a JSON:API document, Data holds a *JSONAPIResource for a single result or a []*JSONAPIResource
+type JSONAPIDocument struct {
+	Data     interface{}        `json:"data"`
+	Included []*JSONAPIResource `json:"included,omitempty"`
+	Meta     interface{}        `json:"meta,omitempty"`
+	Links    *PaginationLinks   `json:"links,omitempty"`
+}
+
+type JSONAPIResource struct {
+	Type          string                          `json:"type"`
+	ID            string                          `json:"id"`
+	Attributes    map[string]interface{}          `json:"attributes,omitempty"`
+	Relationships map[string]*JSONAPIRelationship `json:"relationships,omitempty"`
+}
+
+//JSONAPIRelationship holds the linkage of a relationship, a *JSONAPIResourceIdentifier, a []*JSONAPIResourceIdentifier
+//or nil for an empty to one relationship
+type JSONAPIRelationship struct {
+	Data interface{} `json:"data"`
+}
+
+type JSONAPIResourceIdentifier struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+//JSONAPITyper is implemented by models whose resource type is not their table name
+type JSONAPITyper interface {
+	JSONAPIType() string
+}
+
+//JSONAPISerializer turns the results of a query into a JSON:API document. Attributes are the columns of the model,
+//keyed by column name like the sparse fieldsets, relationships are the preloaded relationships, which are added
+//to the included resources once. Sparse fieldsets list columns, as they do for the query, so they only restrict
+//the attributes and every preloaded relationship is rendered
+type JSONAPISerializer struct {
+	db            *gorm.DB
+	options       OptionsInterface
+	relationships []string
+}
+
+//NewJSONAPISerializer creates a serializer honouring the sparse fieldsets of the options, which may be nil, for the
+//relationships preloaded by the query, see GormAdapter.GetRelationships
+func NewJSONAPISerializer(db *gorm.DB, options OptionsInterface, relationships []string) *JSONAPISerializer {
+	return &JSONAPISerializer{db: db, options: options, relationships: relationships}
+}
+
+//JSONAPISerializer creates a serializer for the results of the query, with the options and relationships it was applied with
+func (g *GormAdapter) JSONAPISerializer() *JSONAPISerializer {
+	return NewJSONAPISerializer(g.db, g.options, g.GetRelationships())
+}
+
+//Document serializes a struct, a slice of structs or a pointer to either
+func (s *JSONAPISerializer) Document(data interface{}) (*JSONAPIDocument, error) {
+	stmt := &gorm.Statement{DB: s.db}
+	if err := stmt.Parse(data); err != nil {
+		return nil, fmt.Errorf("jsonapi requires a model, %v", err)
+	}
+
+	builder := &jsonAPIBuilder{serializer: s, included: make(map[string]*JSONAPIResource)}
+	tree := newRelationshipTree(s.relationships)
+	primary := make(map[string]bool)
+
+	document := &JSONAPIDocument{}
+	value := indirectValue(reflect.ValueOf(data))
+	switch {
+	case !value.IsValid():
+		return document, nil
+	case value.Kind() == reflect.Slice || value.Kind() == reflect.Array:
+		resources := make([]*JSONAPIResource, 0, value.Len())
+		for index := 0; index < value.Len(); index++ {
+			item := indirectValue(value.Index(index))
+			if !item.IsValid() {
+				continue
+			}
+			resource, err := builder.resource(stmt.Schema, item, tree, "")
+			if err != nil {
+				return nil, err
+			}
+			primary[resource.Type+":"+resource.ID] = true
+			resources = append(resources, resource)
+		}
+		document.Data = resources
+	default:
+		resource, err := builder.resource(stmt.Schema, value, tree, "")
+		if err != nil {
+			return nil, err
+		}
+		primary[resource.Type+":"+resource.ID] = true
+		document.Data = resource
+	}
+
+	//a resource is only rendered once in a compound document, the primary data takes precedence
+	for _, key := range builder.order {
+		if !primary[key] {
+			document.Included = append(document.Included, builder.included[key])
+		}
+	}
+	return document, nil
+}
+
+//PaginatedDocument serializes the page with the pagination meta and links
+func (s *JSONAPISerializer) PaginatedDocument(result *PaginationResult) (*JSONAPIDocument, error) {
+	document, err := s.Document(result.Items)
+	if err != nil {
+		return nil, err
+	}
+	document.Meta = result.PaginationMeta
+	document.Links = result.Links
+	return document, nil
+}
+
+//CursorPaginatedDocument serializes the page with the cursors and links
+func (s *JSONAPISerializer) CursorPaginatedDocument(result *CursorPaginationResult) (*JSONAPIDocument, error) {
+	document, err := s.Document(result.Items)
+	if err != nil {
+		return nil, err
+	}
+	document.Meta = result.CursorPaginationMeta
+	document.Links = result.Links
+	return document, nil
+}
+
+//sparseFields returns the fields requested for the resource type or, for included resources, the include path
+func (s *JSONAPISerializer) sparseFields(resourceType string, path string) map[string]bool {
+	if s.options == nil {
+		return nil
+	}
+
+	fields := requestedFields(s.options, resourceType)
+	if len(fields) == 0 {
+		fields = requestedFields(s.options, path)
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+
+	set := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		set[unqualifiedColumn(field)] = true
+	}
+	return set
+}
+
+//relationshipTree holds the preloaded relationships by name, e.g. Wallet.BankAccount becomes Wallet -> BankAccount
+type relationshipTree map[string]relationshipTree
+
+func newRelationshipTree(relationships []string) relationshipTree {
+	tree := relationshipTree{}
+	for _, relationship := range relationships {
+		node := tree
+		for _, name := range strings.Split(relationship, ".") {
+			if _, ok := node[name]; !ok {
+				node[name] = relationshipTree{}
+			}
+			node = node[name]
+		}
+	}
+	return tree
+}
+
+func (t relationshipTree) names() []string {
+	names := make([]string, 0, len(t))
+	for name := range t {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+//jsonAPIBuilder collects the included resources in the order they are first found
+type jsonAPIBuilder struct {
+	serializer *JSONAPISerializer
+	included   map[string]*JSONAPIResource
+	order      []string
+}
+
+func (b *jsonAPIBuilder) resource(s *schema.Schema, value reflect.Value, tree relationshipTree, path string) (*JSONAPIResource, error) {
+	if value.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("jsonapi requires structs, got %s", value.Kind())
+	}
+
+	identifier, err := resourceIdentifier(s, value)
+	if err != nil {
+		return nil, err
+	}
+
+	resource := &JSONAPIResource{Type: identifier.Type, ID: identifier.ID, Attributes: make(map[string]interface{})}
+	fields := b.serializer.sparseFields(resource.Type, path)
+	foreignKeys := relationshipForeignKeys(s, tree)
+	for _, field := range s.Fields {
+		if field.DBName == "" || field.PrimaryKey || !field.Readable || foreignKeys[field] {
+			continue
+		}
+		if fields != nil && !fields[field.DBName] {
+			continue
+		}
+		resource.Attributes[field.DBName] = field.ReflectValueOf(value).Interface()
+	}
+
+	for _, name := range tree.names() {
+		relationship := findRelationship(s, name)
+		if relationship == nil {
+			return nil, fmt.Errorf("jsonapi relationship %s not found on %s", name, s.Name)
+		}
+
+		member := b.serializer.db.NamingStrategy.ColumnName("", relationship.Name)
+		memberPath := member
+		if path != "" {
+			memberPath = path + "." + member
+		}
+
+		linkage, err := b.linkage(relationship, relationship.Field.ReflectValueOf(value), tree[name], memberPath)
+		if err != nil {
+			return nil, err
+		}
+		if resource.Relationships == nil {
+			resource.Relationships = make(map[string]*JSONAPIRelationship)
+		}
+		resource.Relationships[member] = &JSONAPIRelationship{Data: linkage}
+	}
+	return resource, nil
+}
+
+//relationshipForeignKeys returns the foreign keys of the schema that belong to the loaded relationships, e.g. author_id
+//of a post including its author, they are already exposed through the relationship linkage
+func relationshipForeignKeys(s *schema.Schema, tree relationshipTree) map[*schema.Field]bool {
+	foreignKeys := map[*schema.Field]bool{}
+	for _, name := range tree.names() {
+		relationship := findRelationship(s, name)
+		if relationship == nil {
+			continue
+		}
+		for _, reference := range relationship.References {
+			if !reference.OwnPrimaryKey && reference.ForeignKey != nil && reference.ForeignKey.Schema == s {
+				foreignKeys[reference.ForeignKey] = true
+			}
+		}
+	}
+	return foreignKeys
+}
+
+//linkage returns the identifiers of the related resources and adds them to the included resources
+func (b *jsonAPIBuilder) linkage(relationship *schema.Relationship, value reflect.Value, tree relationshipTree, path string) (interface{}, error) {
+	value = indirectValue(value)
+	if value.Kind() == reflect.Slice || value.Kind() == reflect.Array {
+		identifiers := make([]*JSONAPIResourceIdentifier, 0, value.Len())
+		for index := 0; index < value.Len(); index++ {
+			item := indirectValue(value.Index(index))
+			if !item.IsValid() {
+				continue
+			}
+			identifier, err := b.include(relationship.FieldSchema, item, tree, path)
+			if err != nil {
+				return nil, err
+			}
+			identifiers = append(identifiers, identifier)
+		}
+		return identifiers, nil
+	}
+
+	//a to one relationship that was not found is a nil pointer or a struct without a primary key
+	if !value.IsValid() || isZeroPrimaryKey(relationship.FieldSchema, value) {
+		return nil, nil
+	}
+	return b.include(relationship.FieldSchema, value, tree, path)
+}
+
+func (b *jsonAPIBuilder) include(s *schema.Schema, value reflect.Value, tree relationshipTree, path string) (*JSONAPIResourceIdentifier, error) {
+	resource, err := b.resource(s, value, tree, path)
+	if err != nil {
+		return nil, err
+	}
+
+	key := resource.Type + ":" + resource.ID
+	if _, ok := b.included[key]; !ok {
+		b.included[key] = resource
+		b.order = append(b.order, key)
+	}
+	return &JSONAPIResourceIdentifier{Type: resource.Type, ID: resource.ID}, nil
+}
+
+//resourceIdentifier returns the type and id of the struct, composite primary keys are joined with a comma
+func resourceIdentifier(s *schema.Schema, value reflect.Value) (*JSONAPIResourceIdentifier, error) {
+	if len(s.PrimaryFields) == 0 {
+		return nil, fmt.Errorf("jsonapi requires a primary key on %s", s.Name)
+	}
+
+	ids := make([]string, 0, len(s.PrimaryFields))
+	for _, field := range s.PrimaryFields {
+		ids = append(ids, fmt.Sprint(field.ReflectValueOf(value).Interface()))
+	}
+
+	identifier := &JSONAPIResourceIdentifier{Type: s.Table, ID: strings.Join(ids, ",")}
+	if value.CanAddr() {
+		if typer, ok := value.Addr().Interface().(JSONAPITyper); ok {
+			identifier.Type = typer.JSONAPIType()
+			return identifier, nil
+		}
+	}
+	if typer, ok := value.Interface().(JSONAPITyper); ok {
+		identifier.Type = typer.JSONAPIType()
+	}
+	return identifier, nil
+}
+
+func isZeroPrimaryKey(s *schema.Schema, value reflect.Value) bool {
+	for _, field := range s.PrimaryFields {
+		if _, isZero := field.ValueOf(value); !isZero {
+			return false
+		}
+	}
+	return true
+}
+
+//indirectValue follows pointers and interfaces, the returned value is invalid for nil
+func indirectValue(value reflect.Value) reflect.Value {
+	for value.IsValid() && (value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface) {
+		if value.IsNil() {
+			return reflect.Value{}
+		}
+		value = value.Elem()
+	}
+	return value
+}
@@ -0,0 +1,111 @@
+package querybuilder_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/akacokafor/gorm-query-builder/pkg/querybuilder"
+	"github.com/stretchr/testify/assert"
+)
+
+type jsonAPITag struct {
+	ID   uint
+	Name string
+}
+
+func (jsonAPITag) TableName() string {
+	return "relation_tags"
+}
+
+func (jsonAPITag) JSONAPIType() string {
+	return "tags"
+}
+
+func TestJSONAPISerializer_Document(t *testing.T) {
+	db := openRelationDB(t)
+	//the third post shares the author of the first one so that it is included once
+	assert.Nil(t, db.Model(&relationPost{}).Where("title = ?", "third").Update("author_id", 1).Error)
+
+	adapter := querybuilder.NewGormAdapter(db.Model(&relationPost{})).
+		AllowedIncludes([]interface{}{"author.country", "comments"}).
+		AllowedSorts([]interface{}{"id"})
+	got, err := adapter.ExecuteOnUrl("https://example.com/posts?include=author.country,comments&sort=id&fields[relation_posts]=id,title,author_id&fields[relation_countries]=code")
+	assert.Nil(t, err)
+
+	var posts []relationPost
+	assert.Nil(t, got.Find(&posts).Error)
+
+	document, err := adapter.JSONAPISerializer().Document(&posts)
+	assert.Nil(t, err)
+
+	data, err := json.Marshal(document)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{
+		"data": [
+			{"type": "relation_posts", "id": "1", "attributes": {"title": "first"}, "relationships": {
+				"author": {"data": {"type": "relation_authors", "id": "1"}},
+				"comments": {"data": [{"type": "relation_comments", "id": "1"}, {"type": "relation_comments", "id": "2"}]}
+			}},
+			{"type": "relation_posts", "id": "2", "attributes": {"title": "second"}, "relationships": {
+				"author": {"data": {"type": "relation_authors", "id": "2"}},
+				"comments": {"data": [{"type": "relation_comments", "id": "3"}]}
+			}},
+			{"type": "relation_posts", "id": "3", "attributes": {"title": "third"}, "relationships": {
+				"author": {"data": {"type": "relation_authors", "id": "1"}},
+				"comments": {"data": []}
+			}}
+		],
+		"included": [
+			{"type": "relation_countries", "id": "1", "attributes": {"code": "NG"}},
+			{"type": "relation_authors", "id": "1", "attributes": {"name": "Ada"}, "relationships": {
+				"country": {"data": {"type": "relation_countries", "id": "1"}}
+			}},
+			{"type": "relation_comments", "id": "1", "attributes": {"body": "great post", "post_id": 1}},
+			{"type": "relation_comments", "id": "2", "attributes": {"body": "meh", "post_id": 1}},
+			{"type": "relation_countries", "id": "2", "attributes": {"code": "FI"}},
+			{"type": "relation_authors", "id": "2", "attributes": {"name": "Linus"}, "relationships": {
+				"country": {"data": {"type": "relation_countries", "id": "2"}}
+			}},
+			{"type": "relation_comments", "id": "3", "attributes": {"body": "meh", "post_id": 2}}
+		]
+	}`, string(data))
+}
+
+func TestJSONAPISerializer_PaginatedDocument(t *testing.T) {
+	db := openRelationDB(t)
+
+	adapter := querybuilder.NewGormAdapter(db.Model(&jsonAPITag{})).
+		AllowedSorts([]interface{}{"id"})
+	var tags []jsonAPITag
+	result, err := adapter.PaginateIntoOnUrl("https://example.com/tags?sort=-id&size=1", &tags)
+	assert.Nil(t, err)
+
+	document, err := adapter.JSONAPISerializer().PaginatedDocument(result)
+	assert.Nil(t, err)
+
+	data, err := json.Marshal(document)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{
+		"data": [{"type": "tags", "id": "2", "attributes": {"name": "rust"}}],
+		"meta": {"current_page": 1, "per_page": 1, "total": 2, "last_page": 2, "from": 1, "to": 1},
+		"links": {
+			"first": "https://example.com/tags?page=1&size=1&sort=-id",
+			"next": "https://example.com/tags?page=2&size=1&sort=-id",
+			"last": "https://example.com/tags?page=2&size=1&sort=-id"
+		}
+	}`, string(data))
+}
+
+func TestJSONAPISerializer_SingleResource(t *testing.T) {
+	db := openRelationDB(t)
+
+	tag := jsonAPITag{ID: 7, Name: "go"}
+	document, err := querybuilder.NewJSONAPISerializer(db, nil, nil).Document(tag)
+	assert.Nil(t, err)
+	assert.Equal(t, &querybuilder.JSONAPIResource{Type: "tags", ID: "7", Attributes: map[string]interface{}{"name": "go"}}, document.Data)
+	assert.Empty(t, document.Included)
+
+	document, err = querybuilder.NewJSONAPISerializer(db, nil, nil).Document((*jsonAPITag)(nil))
+	assert.Nil(t, err)
+	assert.Nil(t, document.Data)
+}
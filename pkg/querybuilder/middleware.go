@@ -0,0 +1,89 @@
+package querybuilder
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+type optionsContextKey struct{}
+
+//NewContext returns a copy of ctx carrying the parsed options
+func NewContext(ctx context.Context, options *Options) context.Context {
+	return context.WithValue(ctx, optionsContextKey{}, options)
+}
+
+//FromContext returns the options stored on the context by the Middleware
+func FromContext(ctx context.Context) (*Options, bool) {
+	options, ok := ctx.Value(optionsContextKey{}).(*Options)
+	return options, ok
+}
+
+//ErrorResponder writes the response for a request whose query parameters could not be parsed
+type ErrorResponder func(w http.ResponseWriter, r *http.Request, err error)
+
+type MiddlewareConfig struct {
+	//RejectMalformed stops malformed requests, e.g. page=first, with the ErrorResponder instead of passing them on
+	RejectMalformed bool
+	//ErrorResponder defaults to DefaultErrorResponder
+	ErrorResponder ErrorResponder
+	//FilterDelimiter defaults to DefaultFilterDelimiter
+	FilterDelimiter string
+	//DisableFilterSplitting keeps every filter value as supplied, FilterDelimiter is ignored
+	DisableFilterSplitting bool
+}
+
+//Middleware parses the query parameters of every request into *Options, available through FromContext
+func Middleware(next http.Handler) http.Handler {
+	return NewMiddleware(MiddlewareConfig{})(next)
+}
+
+//NewMiddleware creates a Middleware with the given configuration, it works with any router built on net/http
+func NewMiddleware(config MiddlewareConfig) func(http.Handler) http.Handler {
+	if config.ErrorResponder == nil {
+		config.ErrorResponder = DefaultErrorResponder
+	}
+	if config.DisableFilterSplitting {
+		config.FilterDelimiter = ""
+	} else if config.FilterDelimiter == "" {
+		config.FilterDelimiter = DefaultFilterDelimiter
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			options, err := ParseUrlWithDelimiter(r.URL.String(), config.FilterDelimiter)
+			if err != nil {
+				config.ErrorResponder(w, r, err)
+				return
+			}
+
+			if config.RejectMalformed && len(options.GetErrors()) > 0 {
+				validation := &QueryValidationError{}
+				for _, parseError := range options.GetErrors() {
+					validation.Add(parseError, ErrorCodeInvalidParameter, "")
+				}
+				config.ErrorResponder(w, r, validation)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), options)))
+		})
+	}
+}
+
+//DefaultErrorResponder writes a 400 response with a JSON:API errors document
+func DefaultErrorResponder(w http.ResponseWriter, r *http.Request, err error) {
+	var validation *QueryValidationError
+	if !errors.As(err, &validation) {
+		validation = &QueryValidationError{}
+		validation.Add(err, ErrorCodeInvalidParameter, "")
+	}
+
+	w.Header().Set("Content-Type", "application/vnd.api+json")
+	w.WriteHeader(http.StatusBadRequest)
+	if err := json.NewEncoder(w).Encode(validation); err != nil {
+		http.Error(w, fmt.Sprintf("query error: %v", err), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,126 @@
+package querybuilder_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/akacokafor/gorm-query-builder/pkg/querybuilder"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	tests := []struct {
+		name      string
+		handler   func(next http.Handler) http.Handler
+		url       string
+		validator func(t *testing.T, recorder *httptest.ResponseRecorder, options *querybuilder.Options, called bool)
+	}{
+		{
+			name:    "Should store the parsed options on the request context",
+			handler: querybuilder.Middleware,
+			url:     "/users?filter[status]=open&sort=-name&page=2",
+			validator: func(t *testing.T, recorder *httptest.ResponseRecorder, options *querybuilder.Options, called bool) {
+				assert.True(t, called)
+				assert.Equal(t, http.StatusOK, recorder.Code)
+				assert.Equal(t, "open", options.Filters["status"])
+				assert.Equal(t, "name", options.Sort[0].GetName())
+				assert.Equal(t, 2, *options.Page)
+			},
+		},
+		{
+			name:    "Should skip empty sort items",
+			handler: querybuilder.Middleware,
+			url:     "/users?sort=,",
+			validator: func(t *testing.T, recorder *httptest.ResponseRecorder, options *querybuilder.Options, called bool) {
+				assert.True(t, called)
+				assert.Equal(t, http.StatusOK, recorder.Code)
+				assert.Empty(t, options.Sort)
+			},
+		},
+		{
+			name:    "Should skip a bare descending sort",
+			handler: querybuilder.Middleware,
+			url:     "/users?sort=name,-",
+			validator: func(t *testing.T, recorder *httptest.ResponseRecorder, options *querybuilder.Options, called bool) {
+				assert.True(t, called)
+				assert.Len(t, options.Sort, 1)
+				assert.Equal(t, "name", options.Sort[0].GetName())
+			},
+		},
+		{
+			name:    "Should split filter values on the configured delimiter",
+			handler: querybuilder.NewMiddleware(querybuilder.MiddlewareConfig{FilterDelimiter: "|"}),
+			url:     "/users?filter[status]=open|closed",
+			validator: func(t *testing.T, recorder *httptest.ResponseRecorder, options *querybuilder.Options, called bool) {
+				assert.True(t, called)
+				assert.Equal(t, []interface{}{"open", "closed"}, options.Filters["status"])
+			},
+		},
+		{
+			name:    "Should keep filter values whole when splitting is disabled",
+			handler: querybuilder.NewMiddleware(querybuilder.MiddlewareConfig{DisableFilterSplitting: true}),
+			url:     "/users?filter[name]=Doe,%20John",
+			validator: func(t *testing.T, recorder *httptest.ResponseRecorder, options *querybuilder.Options, called bool) {
+				assert.True(t, called)
+				assert.Equal(t, "Doe, John", options.Filters["name"])
+			},
+		},
+		{
+			name:    "Should pass malformed requests on by default",
+			handler: querybuilder.Middleware,
+			url:     "/users?page=first",
+			validator: func(t *testing.T, recorder *httptest.ResponseRecorder, options *querybuilder.Options, called bool) {
+				assert.True(t, called)
+				assert.Len(t, options.GetErrors(), 1)
+			},
+		},
+		{
+			name:    "Should reject malformed requests when configured",
+			handler: querybuilder.NewMiddleware(querybuilder.MiddlewareConfig{RejectMalformed: true}),
+			url:     "/users?page=first&size=ten",
+			validator: func(t *testing.T, recorder *httptest.ResponseRecorder, options *querybuilder.Options, called bool) {
+				assert.False(t, called)
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+				var document struct {
+					Errors []struct {
+						Code string `json:"code"`
+					} `json:"errors"`
+				}
+				assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &document))
+				assert.Len(t, document.Errors, 2)
+				assert.Equal(t, querybuilder.ErrorCodeInvalidPage, document.Errors[0].Code)
+			},
+		},
+		{
+			name: "Should use the configured error responder",
+			handler: querybuilder.NewMiddleware(querybuilder.MiddlewareConfig{
+				RejectMalformed: true,
+				ErrorResponder: func(w http.ResponseWriter, r *http.Request, err error) {
+					w.WriteHeader(http.StatusUnprocessableEntity)
+				},
+			}),
+			url: "/users?size=ten",
+			validator: func(t *testing.T, recorder *httptest.ResponseRecorder, options *querybuilder.Options, called bool) {
+				assert.False(t, called)
+				assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var options *querybuilder.Options
+			called := false
+			handler := tt.handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+				options, _ = querybuilder.FromContext(r.Context())
+			}))
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, tt.url, nil))
+			tt.validator(t, recorder, options, called)
+		})
+	}
+}
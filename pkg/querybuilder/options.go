@@ -2,9 +2,9 @@ package querybuilder
 
 import (
 	"fmt"
-	"log"
 	"net/url"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 )
@@ -48,17 +48,25 @@ type Options struct {
 	Query    *string
 	Page     *int
 	Size     *int
+	Cursor   *string
 	Filters  map[string]interface{}
+	//RawFilters holds the filter values as supplied, in the same shape as Filters, they are coerced by the adapter
+	//to the types of the filtered columns
+	RawFilters map[string]interface{}
 	Sort     []Sortable
 	Includes []string
 	Fields   map[string][]string
 	Errors   []error
 	filterRegex *regexp.Regexp
 	fieldsRegex *regexp.Regexp
+	filterDelimiter string
 }
 
+//DefaultFilterDelimiter separates multiple values supplied to a single filter, e.g. filter[status]=open,closed
+const DefaultFilterDelimiter = ","
+
 func NewOptions() (*Options, error) {
-	filterRegex, err := regexp.Compile(`filter\[(.+)\]`)
+	filterRegex, err := regexp.Compile(`^filter\[([^\[\]]+)\](?:\[([^\[\]]+)\])?$`)
 	if err != nil {
 		return nil, err
 	}
@@ -70,6 +78,7 @@ func NewOptions() (*Options, error) {
 	return &Options{
 		filterRegex: filterRegex,
 		fieldsRegex: fieldsRegex,
+		filterDelimiter: DefaultFilterDelimiter,
 	}, nil
 }
 
@@ -85,10 +94,24 @@ func (p *Options) GetQuery() *string {
 	return p.Query
 }
 
+//GetErrors returns the errors encountered while parsing the query parameters
+func (p *Options) GetErrors() []error {
+	return p.Errors
+}
+
+func (p *Options) GetCursor() *string {
+	return p.Cursor
+}
+
 func (p *Options) GetFilters() map[string]interface{} {
 	return p.Filters
 }
 
+//GetRawFilters returns the filter values as strings, before any type was guessed for them
+func (p *Options) GetRawFilters() map[string]interface{} {
+	return p.RawFilters
+}
+
 func (p *Options) GetIncludes() []string  {
 	return p.Includes
 }
@@ -103,25 +126,113 @@ func (p *Options) GetFields() map[string][]string  {
 
 
 func (p *Options) setIncludes(queryParams url.Values) *Options {
-	log.Print(queryParams.Get("include"))
-	p.Includes = strings.Split(queryParams.Get("include"),",")
+	p.Includes = splitIncludes(queryParams.Get("include"))
 	return p
 }
 
+//splitIncludes splits the include parameter on commas, dropping empty includes such as the one a missing
+//parameter or a trailing comma would produce
+func splitIncludes(val string) []string {
+	var includes []string
+	for _, include := range strings.Split(val, ",") {
+		if include = strings.TrimSpace(include); include != "" {
+			includes = append(includes, include)
+		}
+	}
+	return includes
+}
+
 func (p *Options) setFilters(queryParams url.Values) *Options {
 	if len(p.Filters) == 0 {
 		p.Filters = make(map[string]interface{})
 	}
-	for k, val := range queryParams {
+	if len(p.RawFilters) == 0 {
+		p.RawFilters = make(map[string]interface{})
+	}
+	//nested keys are read first and the keys in order, so a filter supplied both with and without an operator,
+	//e.g. filter[price]=10&filter[price][gte]=5, always keeps the operators and reports the plain value
+	var plainKeys, nestedKeys []string
+	for k := range queryParams {
+		result := p.filterRegex.FindStringSubmatch(k)
+		if len(result) > 2 && result[2] != "" {
+			nestedKeys = append(nestedKeys, k)
+		} else if len(result) > 1 {
+			plainKeys = append(plainKeys, k)
+		}
+	}
+	sort.Strings(plainKeys)
+	sort.Strings(nestedKeys)
+
+	for _, k := range append(nestedKeys, plainKeys...) {
+		val := queryParams[k]
 		result := p.filterRegex.FindStringSubmatch(k)
 		if len(result) > 1 && len(val) > 0 {
 			filterKey := result[1]
-			p.Filters[filterKey] = p.simpleParseString(val[0])
+			if _, hasOperators := p.Filters[filterKey].(map[string]interface{}); hasOperators && result[2] == "" {
+				p.Errors = append(p.Errors, &QueryParameterError{
+					Code:      ErrorCodeInvalidFilter,
+					Parameter: k,
+					Value:     strings.Join(val, ","),
+					Detail:    fmt.Sprintf("filter %s can not be supplied both with and without an operator", filterKey),
+					Err:       ErrInvalidFilterQuery,
+				})
+				continue
+			}
+			if len(result) > 2 && result[2] != "" {
+				//nested keys such as filter[price][gte]=100 are collected into a map of operator to value
+				operators, ok := p.Filters[filterKey].(map[string]interface{})
+				if !ok {
+					operators = make(map[string]interface{})
+				}
+				operators[result[2]] = p.parseFilterValues(val)
+				p.Filters[filterKey] = operators
+
+				rawOperators, ok := p.RawFilters[filterKey].(map[string]interface{})
+				if !ok {
+					rawOperators = make(map[string]interface{})
+				}
+				rawOperators[result[2]] = p.rawFilterValues(val)
+				p.RawFilters[filterKey] = rawOperators
+				continue
+			}
+			p.Filters[filterKey] = p.parseFilterValues(val)
+			p.RawFilters[filterKey] = p.rawFilterValues(val)
 		}
 	}
 	return p
 }
 
+//parseFilterValues collects repeated values and splits a value supplied once on the delimiter, a repeated filter keeps
+//each value whole so that values containing the delimiter can be supplied. A single value is kept as a scalar and
+//multiple values become a slice
+func (p *Options) parseFilterValues(val []string) interface{} {
+	return p.splitFilterValues(val, p.simpleParseString)
+}
+
+func (p *Options) rawFilterValues(val []string) interface{} {
+	return p.splitFilterValues(val, func(item string) interface{} {
+		return item
+	})
+}
+
+func (p *Options) splitFilterValues(val []string, parse func(item string) interface{}) interface{} {
+	var items []interface{}
+	for _, item := range val {
+		parts := []string{item}
+		if p.filterDelimiter != "" && len(val) == 1 {
+			parts = strings.Split(item, p.filterDelimiter)
+		}
+		for _, part := range parts {
+			items = append(items, parse(part))
+		}
+	}
+
+	if len(items) == 1 {
+		return items[0]
+	}
+	return items
+}
+
 func (p *Options) setFields(queryParams url.Values) *Options {
 	if len(p.Fields) == 0 {
 		p.Fields = make(map[string][]string)
@@ -142,6 +253,10 @@ func (p *Options) setFields(queryParams url.Values) *Options {
 }
 
 func (p *Options) simpleParseString(item string) interface{} {
+	return simpleParseString(item)
+}
+
+func simpleParseString(item string) interface{} {
 	num, err := strconv.Atoi(item)
 	if err == nil {
 		return num
@@ -171,6 +286,9 @@ func (p *Options) setSort(queryParams url.Values) *Options {
 func (p *Options) addSort(val string) {
 	sortList := strings.Split(val, ",")
 	for _, sortItem := range sortList {
+		if sortItem == "" || sortItem == "-" {
+			continue
+		}
 		s := Sort{Ascending: true, Name: sortItem}
 		if sortItem[:1] == "-" {
 			s.Ascending = false
@@ -185,7 +303,13 @@ func (p *Options) setSize(queryParams url.Values) *Options {
 	if val != "" {
 		sizeInt, err := strconv.Atoi(val)
 		if err != nil {
-			p.Errors = append(p.Errors, fmt.Errorf("size parse error: %w", err))
+			p.Errors = append(p.Errors, &QueryParameterError{
+				Code:      ErrorCodeInvalidSize,
+				Parameter: "size",
+				Value:     val,
+				Detail:    "size parse error",
+				Err:       err,
+			})
 			return p
 		}
 		p.Size = &sizeInt
@@ -199,7 +323,13 @@ func (p *Options) setPage(queryParams url.Values) *Options {
 	if val != "" {
 		pageInt, err := strconv.Atoi(val)
 		if err != nil {
-			p.Errors = append(p.Errors, fmt.Errorf("page parse error: %w", err))
+			p.Errors = append(p.Errors, &QueryParameterError{
+				Code:      ErrorCodeInvalidPage,
+				Parameter: "page",
+				Value:     val,
+				Detail:    "page parse error",
+				Err:       err,
+			})
 			return p
 		}
 		if pageInt <= 0 {
@@ -219,7 +349,28 @@ func (p *Options) setQuery(queryParams url.Values) *Options {
 	return p
 }
 
+func (p *Options) setCursor(queryParams url.Values) *Options {
+	cursorVal := queryParams.Get("cursor")
+	if cursorVal != "" {
+		p.Cursor = &cursorVal
+	}
+	return p
+}
+
+//parseUrl parses the url with the configured filter delimiter
+func (c queryConfig) parseUrl(originUrl string) (*Options, error) {
+	if c.filterDelimiter != nil {
+		return ParseUrlWithDelimiter(originUrl, *c.filterDelimiter)
+	}
+	return ParseUrl(originUrl)
+}
+
 func ParseUrl(originUrl string) (*Options, error) {
+	return ParseUrlWithDelimiter(originUrl, DefaultFilterDelimiter)
+}
+
+//ParseUrlWithDelimiter parses the url, splitting filter values on the delimiter, an empty delimiter disables splitting
+func ParseUrlWithDelimiter(originUrl string, delimiter string) (*Options, error) {
 	uriParams, err := url.Parse(originUrl)
 	if err != nil {
 		return nil, err
@@ -228,10 +379,12 @@ func ParseUrl(originUrl string) (*Options, error) {
 	if err != nil {
 		return nil, err
 	}
+	p.filterDelimiter = delimiter
 	queryParams := uriParams.Query()
 	p.setQuery(queryParams)
 	p.setPage(queryParams)
 	p.setSize(queryParams)
+	p.setCursor(queryParams)
 	p.setSort(queryParams)
 	p.setFilters(queryParams)
 	p.setIncludes(queryParams)
@@ -0,0 +1,245 @@
+package querybuilder
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//Encode serializes the options back to a query string that ParseUrl reads into the same options. Parameters are
+//sorted by name and filter operators by operator, so equal options always produce the same string.
+//Filters are written from RawFilters when present so values such as 01234 keep their leading zeros,
+//multiple values are joined with the filter delimiter, or repeated when splitting is disabled or a value contains it
+func (p *Options) Encode() string {
+	var parameters []string
+	add := func(key string, value string) {
+		parameters = append(parameters, escapeQueryComponent(key)+"="+escapeQueryComponent(value))
+	}
+
+	if p.Query != nil && *p.Query != "" {
+		add("q", *p.Query)
+	}
+	if p.Page != nil {
+		add("page", strconv.Itoa(*p.Page))
+	}
+	if p.Size != nil {
+		add("size", strconv.Itoa(*p.Size))
+	}
+	if p.Cursor != nil && *p.Cursor != "" {
+		add("cursor", *p.Cursor)
+	}
+
+	if len(p.Sort) > 0 {
+		sorts := make([]string, 0, len(p.Sort))
+		for _, s := range p.Sort {
+			if s.IsAscending() {
+				sorts = append(sorts, s.GetName())
+			} else {
+				sorts = append(sorts, "-"+s.GetName())
+			}
+		}
+		add("sort", strings.Join(sorts, ","))
+	}
+
+	if len(p.Includes) > 0 {
+		add("include", strings.Join(p.Includes, ","))
+	}
+
+	for name, fields := range p.Fields {
+		if len(fields) > 0 {
+			add(fmt.Sprintf("fields[%s]", name), strings.Join(fields, ","))
+		}
+	}
+
+	for key, value := range p.Filters {
+		if raw, ok := p.RawFilters[key]; ok {
+			value = raw
+		}
+
+		if operators, ok := value.(map[string]interface{}); ok {
+			for operator, operand := range operators {
+				for _, encoded := range p.encodeFilterValue(operand) {
+					add(fmt.Sprintf("filter[%s][%s]", key, operator), encoded)
+				}
+			}
+			continue
+		}
+		for _, encoded := range p.encodeFilterValue(value) {
+			add(fmt.Sprintf("filter[%s]", key), encoded)
+		}
+	}
+
+	//the parameters start with their escaped key, so sorting them orders by key and keeps repeated values in order
+	sort.SliceStable(parameters, func(i, j int) bool {
+		return strings.SplitN(parameters[i], "=", 2)[0] < strings.SplitN(parameters[j], "=", 2)[0]
+	})
+	return strings.Join(parameters, "&")
+}
+
+//WithPage returns a copy of the options requesting the page
+func (p *Options) WithPage(page int) *Options {
+	c := p.clone()
+	c.Page = &page
+	return c
+}
+
+//WithSize returns a copy of the options requesting the page size
+func (p *Options) WithSize(size int) *Options {
+	c := p.clone()
+	c.Size = &size
+	return c
+}
+
+//WithCursor returns a copy of the options requesting the cursor, an empty cursor requests the first page
+func (p *Options) WithCursor(cursor string) *Options {
+	c := p.clone()
+	c.Cursor = nil
+	if cursor != "" {
+		c.Cursor = &cursor
+	}
+	return c
+}
+
+//WithSort returns a copy of the options sorted by the sorts, replacing the current sort
+func (p *Options) WithSort(sorts ...Sortable) *Options {
+	c := p.clone()
+	c.Sort = append([]Sortable(nil), sorts...)
+	return c
+}
+
+//WithFilter returns a copy of the options with the filter set to the value, replacing any current value.
+//The value is a single value, a []interface{} of values or a map[string]interface{} of operator to value
+func (p *Options) WithFilter(key string, value interface{}) *Options {
+	c := p.clone()
+	if c.Filters == nil {
+		c.Filters = make(map[string]interface{})
+	}
+	if c.RawFilters == nil {
+		c.RawFilters = make(map[string]interface{})
+	}
+	c.Filters[key] = value
+	c.RawFilters[key] = rawFilterValue(value)
+	return c
+}
+
+//WithoutFilter returns a copy of the options without the filter
+func (p *Options) WithoutFilter(key string) *Options {
+	c := p.clone()
+	delete(c.Filters, key)
+	delete(c.RawFilters, key)
+	return c
+}
+
+//clone copies the options so that changing the copy can not change the original
+func (p *Options) clone() *Options {
+	c := *p
+	if p.Query != nil {
+		query := *p.Query
+		c.Query = &query
+	}
+	if p.Page != nil {
+		page := *p.Page
+		c.Page = &page
+	}
+	if p.Size != nil {
+		size := *p.Size
+		c.Size = &size
+	}
+	if p.Cursor != nil {
+		cursor := *p.Cursor
+		c.Cursor = &cursor
+	}
+	c.Sort = append([]Sortable(nil), p.Sort...)
+	c.Includes = append([]string(nil), p.Includes...)
+	c.Errors = append([]error(nil), p.Errors...)
+	c.Filters = copyFilters(p.Filters)
+	c.RawFilters = copyFilters(p.RawFilters)
+	if p.Fields != nil {
+		c.Fields = make(map[string][]string, len(p.Fields))
+		for name, fields := range p.Fields {
+			c.Fields[name] = append([]string(nil), fields...)
+		}
+	}
+	return &c
+}
+
+//encodeFilterValue formats a single value or each value of a list, lists are joined with the delimiter unless one of
+//the values contains it, then each value is repeated as a parameter of its own
+func (p *Options) encodeFilterValue(value interface{}) []string {
+	values, isList := value.([]interface{})
+	if !isList {
+		return []string{formatFilterValue(value)}
+	}
+
+	join := p.filterDelimiter != "" && len(values) > 0
+	encoded := make([]string, 0, len(values))
+	for _, item := range values {
+		formatted := formatFilterValue(item)
+		if p.filterDelimiter != "" && strings.Contains(formatted, p.filterDelimiter) {
+			join = false
+		}
+		encoded = append(encoded, formatted)
+	}
+	if join {
+		return []string{strings.Join(encoded, p.filterDelimiter)}
+	}
+	return encoded
+}
+
+func formatFilterValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case *time.Time:
+		return v.Format(time.RFC3339Nano)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+//rawFilterValue formats the value the way ParseUrl keeps it in RawFilters
+func rawFilterValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case []interface{}:
+		values := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			values = append(values, rawFilterValue(item))
+		}
+		return values
+	case map[string]interface{}:
+		operators := make(map[string]interface{}, len(v))
+		for operator, operand := range v {
+			operators[operator] = rawFilterValue(operand)
+		}
+		return operators
+	default:
+		return formatFilterValue(v)
+	}
+}
+
+func copyFilters(filters map[string]interface{}) map[string]interface{} {
+	if filters == nil {
+		return nil
+	}
+	c := make(map[string]interface{}, len(filters))
+	for key, value := range filters {
+		if operators, ok := value.(map[string]interface{}); ok {
+			value = copyFilters(operators)
+		}
+		if values, ok := value.([]interface{}); ok {
+			value = append([]interface{}(nil), values...)
+		}
+		c[key] = value
+	}
+	return c
+}
+
+//escapeQueryComponent escapes the key or value, leaving the brackets, commas and colons readable
+func escapeQueryComponent(value string) string {
+	return strings.NewReplacer("%5B", "[", "%5D", "]", "%2C", ",", "%3A", ":").Replace(url.QueryEscape(value))
+}
@@ -200,6 +200,86 @@ func TestParseUrl(t *testing.T) {
 				assert.Equal(t, "id", p.Fields["user"][0])
 			},
 		},
+		{
+			name: "should successfully parse url with nested operator filters",
+			args: args{
+				originUrl: "https://example.com?filter[price][gte]=100&filter[price][lte]=500&filter[status]=open",
+			},
+			validate: func(t *testing.T, p *querybuilder.Options, err error) {
+				assert.Nil(t, err)
+				assert.Len(t, p.Filters, 2)
+				assert.Equal(t, map[string]interface{}{"gte": 100, "lte": 500}, p.Filters["price"])
+				assert.Equal(t, "open", p.Filters["status"])
+			},
+		},
+		{
+			name: "should successfully parse url with comma separated and repeated filter values",
+			args: args{
+				originUrl: "https://example.com?filter[status]=open,closed&filter[id]=1&filter[id]=2",
+			},
+			validate: func(t *testing.T, p *querybuilder.Options, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, []interface{}{"open", "closed"}, p.Filters["status"])
+				assert.Equal(t, []interface{}{1, 2}, p.Filters["id"])
+			},
+		},
+		{
+			name: "should keep repeated filter values containing the delimiter whole",
+			args: args{
+				originUrl: "https://example.com?filter[name]=doe,john&filter[name]=ada",
+			},
+			validate: func(t *testing.T, p *querybuilder.Options, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, []interface{}{"doe,john", "ada"}, p.Filters["name"])
+			},
+		},
+		{
+			name: "should report a filter supplied both with and without an operator",
+			args: args{
+				originUrl: "https://example.com?filter[price]=10&filter[price][gte]=5",
+			},
+			validate: func(t *testing.T, p *querybuilder.Options, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, map[string]interface{}{"gte": 5}, p.Filters["price"])
+				assert.Equal(t, map[string]interface{}{"gte": "5"}, p.GetRawFilters()["price"])
+				if assert.Len(t, p.Errors, 1) {
+					assert.ErrorIs(t, p.Errors[0], querybuilder.ErrInvalidFilterQuery)
+				}
+			},
+		},
+		{
+			name: "should drop empty includes",
+			args: args{
+				originUrl: "https://example.com?include=,user,, mobile ,",
+			},
+			validate: func(t *testing.T, p *querybuilder.Options, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, []string{"user", "mobile"}, p.Includes)
+			},
+		},
+		{
+			name: "should not include anything without an include parameter",
+			args: args{
+				originUrl: "https://example.com?q=search",
+			},
+			validate: func(t *testing.T, p *querybuilder.Options, err error) {
+				assert.Nil(t, err)
+				assert.Empty(t, p.Includes)
+			},
+		},
+		{
+			name: "should keep the raw filter values as strings",
+			args: args{
+				originUrl: "https://example.com?filter[zip]=01234&filter[code]=true&filter[price][gte]=100",
+			},
+			validate: func(t *testing.T, p *querybuilder.Options, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, 1234, p.Filters["zip"])
+				assert.Equal(t, "01234", p.GetRawFilters()["zip"])
+				assert.Equal(t, "true", p.GetRawFilters()["code"])
+				assert.Equal(t, map[string]interface{}{"gte": "100"}, p.GetRawFilters()["price"])
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -208,3 +288,121 @@ func TestParseUrl(t *testing.T) {
 		})
 	}
 }
+
+func TestParseUrlWithDelimiter(t *testing.T) {
+	p, err := querybuilder.ParseUrlWithDelimiter("https://example.com?filter[name]=doe,john&filter[status]=open|closed", "|")
+	assert.Nil(t, err)
+	assert.Equal(t, "doe,john", p.Filters["name"])
+	assert.Equal(t, []interface{}{"open", "closed"}, p.Filters["status"])
+
+	p, err = querybuilder.ParseUrlWithDelimiter("https://example.com?filter[name]=doe,john", "")
+	assert.Nil(t, err)
+	assert.Equal(t, "doe,john", p.Filters["name"])
+}
+
+func TestOptions_Encode(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		expected string
+	}{
+		{
+			name:     "Should encode an empty query string",
+			url:      "https://example.com",
+			expected: "",
+		},
+		{
+			name:     "Should encode the parameters sorted by name",
+			url:      "https://example.com?sort=-created_at,name&size=10&page=2&q=golang+tips&include=wallet,wallet.bank_account",
+			expected: "include=wallet,wallet.bank_account&page=2&q=golang+tips&size=10&sort=-created_at,name",
+		},
+		{
+			name:     "Should encode the fields of each table",
+			url:      "https://example.com?fields[wallet]=id,balance&fields[users]=id,name",
+			expected: "fields[users]=id,name&fields[wallet]=id,balance",
+		},
+		{
+			name:     "Should encode filters as supplied",
+			url:      "https://example.com?filter[zip]=01234&filter[status]=open,closed&filter[name]=john+doe",
+			expected: "filter[name]=john+doe&filter[status]=open,closed&filter[zip]=01234",
+		},
+		{
+			name:     "Should encode filter operators",
+			url:      "https://example.com?filter[price][lte]=200&filter[price][gte]=100&filter[created_at]=between:2024-01-01,2024-02-01",
+			expected: "filter[created_at]=between:2024-01-01,2024-02-01&filter[price][gte]=100&filter[price][lte]=200",
+		},
+		{
+			name:     "Should escape reserved characters",
+			url:      "https://example.com?q=a%26b%3Dc&cursor=abc%2B%2F%3D",
+			expected: "cursor=abc%2B%2F%3D&q=a%26b%3Dc",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options, err := querybuilder.ParseUrl(tt.url)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expected, options.Encode())
+
+			parsed, err := querybuilder.ParseUrl("https://example.com?" + options.Encode())
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expected, parsed.Encode())
+			assert.Equal(t, options.Filters, parsed.Filters)
+		})
+	}
+}
+
+func TestOptions_With(t *testing.T) {
+	options, err := querybuilder.ParseUrl("https://example.com?filter[status]=open&sort=name&page=3")
+	assert.Nil(t, err)
+
+	tests := []struct {
+		name     string
+		options  *querybuilder.Options
+		expected string
+	}{
+		{
+			name:     "Should change the page",
+			options:  options.WithPage(4),
+			expected: "filter[status]=open&page=4&sort=name",
+		},
+		{
+			name:     "Should replace the sort",
+			options:  options.WithSort(&querybuilder.Sort{Name: "created_at"}, &querybuilder.Sort{Name: "id", Ascending: true}),
+			expected: "filter[status]=open&page=3&sort=-created_at,id",
+		},
+		{
+			name:     "Should add a filter",
+			options:  options.WithFilter("role", []interface{}{"admin", 5}),
+			expected: "filter[role]=admin,5&filter[status]=open&page=3&sort=name",
+		},
+		{
+			name:     "Should repeat a filter when one of its values contains the delimiter",
+			options:  options.WithFilter("name", []interface{}{1, "doe,john"}),
+			expected: "filter[name]=1&filter[name]=doe,john&filter[status]=open&page=3&sort=name",
+		},
+		{
+			name:     "Should replace a filter with operators",
+			options:  options.WithFilter("status", map[string]interface{}{"ne": "closed"}),
+			expected: "filter[status][ne]=closed&page=3&sort=name",
+		},
+		{
+			name:     "Should remove a filter",
+			options:  options.WithoutFilter("status").WithPage(1),
+			expected: "page=1&sort=name",
+		},
+		{
+			name:     "Should not change the original options",
+			options:  options,
+			expected: "filter[status]=open&page=3&sort=name",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.options.Encode())
+
+			parsed, err := querybuilder.ParseUrl("https://example.com?" + tt.options.Encode())
+			assert.Nil(t, err)
+			assert.Equal(t, tt.options.Filters, parsed.Filters)
+		})
+	}
+}
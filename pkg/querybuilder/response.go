@@ -0,0 +1,39 @@
+package querybuilder
+
+import (
+	"net/http"
+)
+
+//RequestUrl returns the absolute url of the request, the scheme and host are taken from the request itself so behind a
+//proxy the public url should be set with BaseUrl
+func RequestUrl(r *http.Request) string {
+	requestUrl := *r.URL
+	if requestUrl.Host == "" {
+		requestUrl.Host = r.Host
+	}
+	if requestUrl.Scheme == "" {
+		requestUrl.Scheme = "http"
+		if r.TLS != nil {
+			requestUrl.Scheme = "https"
+		}
+	}
+	return requestUrl.String()
+}
+
+//PaginateResponse paginates the query of the request into dest and returns the HTTP status and the body to respond
+//with, the pagination result or the errors document of ErrorResponse. The error is returned as well so that it can be
+//logged, the body of a 500 does not describe it
+func (g *GormAdapter) PaginateResponse(r *http.Request, dest interface{}) (int, interface{}, error) {
+	return g.PaginateUrlResponse(RequestUrl(r), dest)
+}
+
+//PaginateUrlResponse is PaginateResponse for frameworks that are not built on net/http, url should be the absolute url
+//of the request for the pagination links to be absolute
+func (g *GormAdapter) PaginateUrlResponse(url string, dest interface{}) (int, interface{}, error) {
+	result, err := g.PaginateIntoOnUrl(url, dest)
+	if err != nil {
+		status, document := ErrorResponse(err)
+		return status, document, err
+	}
+	return http.StatusOK, result, nil
+}
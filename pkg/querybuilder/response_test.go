@@ -0,0 +1,57 @@
+package querybuilder_test
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/akacokafor/gorm-query-builder/pkg/querybuilder"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestUrl(t *testing.T) {
+	request := httptest.NewRequest(http.MethodGet, "/users?page=2", nil)
+	request.Host = "api.example.com"
+	assert.Equal(t, "http://api.example.com/users?page=2", querybuilder.RequestUrl(request))
+
+	request.TLS = &tls.ConnectionState{}
+	assert.Equal(t, "https://api.example.com/users?page=2", querybuilder.RequestUrl(request))
+}
+
+func TestGormAdapter_PaginateResponse(t *testing.T) {
+	db := openPaginationDB(t, 12)
+	newAdapter := func() *querybuilder.GormAdapter {
+		return querybuilder.NewGormAdapter(db.Model(&paginatedUser{})).
+			AllowedFilters([]interface{}{querybuilder.NewGormAllowedFilterExact("status")})
+	}
+
+	t.Run("Should respond with the page and absolute links", func(t *testing.T) {
+		var users []paginatedUser
+		status, body, err := newAdapter().PaginateResponse(httptest.NewRequest(http.MethodGet, "/users?page=2&size=5", nil), &users)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, status)
+
+		result, ok := body.(*querybuilder.PaginationResult)
+		assert.True(t, ok)
+		assert.Len(t, users, 5)
+		assert.Equal(t, "http://example.com/users?page=3&size=5", result.Links.Next)
+	})
+
+	t.Run("Should respond with the errors document for invalid queries", func(t *testing.T) {
+		var users []paginatedUser
+		status, body, err := newAdapter().PaginateResponse(httptest.NewRequest(http.MethodGet, "/users?filter[password]=secret", nil), &users)
+		assert.ErrorIs(t, err, querybuilder.ErrInvalidFilterQuery)
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.IsType(t, &querybuilder.QueryValidationError{}, body)
+	})
+
+	t.Run("Should return the error behind a 500 along with the errors document", func(t *testing.T) {
+		var users []paginatedUser
+		status, _, err := querybuilder.NewGormAdapter(db.Table("missing_users")).
+			PaginateResponse(httptest.NewRequest(http.MethodGet, "/users", nil), &users)
+		assert.Equal(t, http.StatusInternalServerError, status)
+		assert.Contains(t, fmt.Sprint(err), "missing_users")
+	})
+}